@@ -0,0 +1,172 @@
+package backoff
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// AttemptAbandoned is returned for an attempt that did not return after its
+// context was done, see WithAsyncAttempts
+const AttemptAbandoned = Error("attempt abandoned")
+
+// Overrun describes an abandoned attempt that returned after its context was
+// done, see WithOverrunWatchdog.
+type Overrun struct {
+	// AttemptID is the ID of the Attempt
+	AttemptID string
+	// Number is the attempt number
+	Number int
+	// Overrun is how long the attempt ran after it was abandoned
+	Overrun time.Duration
+	// Err is the discarded error of the attempt
+	Err error
+}
+
+// WithAsyncAttempts runs each attempt in its own goroutine. When the context
+// of the attempt is done, for example by the per try timeout, the loop stops
+// waiting for the attempt, counts it as failed with an error wrapping
+// AttemptAbandoned and the context cause, and carries on. A function that
+// ignores its context can then not block the loop.
+//
+// The goroutine of an abandoned attempt keeps running until the function
+// returns and its result is discarded, including a panic. AsyncStats reports
+// how many attempts were abandoned and how many of them are still running.
+func WithAsyncAttempts() Options {
+	return func(bo *Backoff) {
+		if bo.asyncAttempts == nil {
+			bo.asyncAttempts = &asyncAttempts{}
+		}
+	}
+}
+
+// AsyncStats counts the attempts abandoned with WithAsyncAttempts.
+type AsyncStats struct {
+	// Abandoned is the number of attempts abandoned
+	Abandoned int64
+	// Running is the number of abandoned attempts that have not returned yet
+	Running int64
+	// LongestOverrun is the longest Overrun of the abandoned attempts that
+	// returned
+	LongestOverrun time.Duration
+}
+
+// AsyncStats returns the counts of the attempts abandoned with
+// WithAsyncAttempts, the zero AsyncStats without it. Copies made by call
+// options share the counts.
+func (b *Backoff) AsyncStats() AsyncStats {
+	if b.asyncAttempts == nil {
+		return AsyncStats{}
+	}
+	return AsyncStats{
+		Abandoned: b.asyncAttempts.abandoned.Load(),
+		Running:   b.asyncAttempts.running.Load(),
+
+		LongestOverrun: time.Duration(b.asyncAttempts.longest.Load()),
+	}
+}
+
+// WithOverrunWatchdog finds functions that do not honor their context, for
+// example callbacks wrapping legacy blocking code. It runs the attempts like
+// WithAsyncAttempts and calls onOverrun from the goroutine of an abandoned
+// attempt when it finally returns. AsyncStats reports the attempts still
+// running and the longest overrun. Use LogOverruns to log them.
+func WithOverrunWatchdog(onOverrun func(Overrun)) Options {
+	return func(bo *Backoff) {
+		WithAsyncAttempts()(bo)
+		bo.asyncAttempts.onOverrun = onOverrun
+	}
+}
+
+// LogOverruns returns a function for WithOverrunWatchdog that logs each
+// Overrun to logger.
+func LogOverruns(logger *log.Logger) func(Overrun) {
+	return func(o Overrun) {
+		logger.Printf("backoff: attempt %s returned %s after it was abandoned: %v", o.AttemptID, o.Overrun, o.Err)
+	}
+}
+
+type asyncAttempts struct {
+	abandoned atomic.Int64
+	running   atomic.Int64
+	longest   atomic.Int64
+	onOverrun func(Overrun)
+}
+
+// overrun records an abandoned attempt that returned
+func (s *asyncAttempts) overrun(ctx context.Context, since time.Time, err error) {
+	d := time.Since(since)
+	for {
+		longest := s.longest.Load()
+		if int64(d) <= longest || s.longest.CompareAndSwap(longest, int64(d)) {
+			break
+		}
+	}
+	if s.onOverrun == nil {
+		return
+	}
+	o := Overrun{Overrun: d, Err: err}
+	if a, ok := ScheduleFromContext(ctx); ok {
+		o.AttemptID, o.Number = a.ID, a.Number
+	}
+	s.onOverrun(o)
+}
+
+type asyncResult struct {
+	err      error
+	panicked bool
+	panic    interface{}
+}
+
+// states of an asynchronous attempt
+const (
+	asyncRunning int32 = iota
+	asyncReturned
+	asyncAbandoned
+)
+
+// call runs fn in a goroutine and returns its error, or abandons it when ctx
+// is done first. A panic of fn is raised again in the calling goroutine.
+func (s *asyncAttempts) call(ctx context.Context, fn Operation) error {
+	done := make(chan asyncResult, 1)
+	var state atomic.Int32
+	var abandonedAt time.Time
+	go func() {
+		var res asyncResult
+		defer func() {
+			if r := recover(); r != nil {
+				res.panicked, res.panic = true, r
+			}
+			if state.CompareAndSwap(asyncRunning, asyncReturned) {
+				done <- res
+				return
+			}
+			s.running.Add(-1)
+			if res.panicked {
+				res.err = fmt.Errorf("attempt panicked: %v", res.panic)
+			}
+			s.overrun(ctx, abandonedAt, res.err)
+		}()
+		res.err = fn(ctx)
+	}()
+	var res asyncResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		s.running.Add(1)
+		abandonedAt = time.Now()
+		if state.CompareAndSwap(asyncRunning, asyncAbandoned) {
+			s.abandoned.Add(1)
+			return fmt.Errorf("%w: %w", AttemptAbandoned, context.Cause(ctx))
+		}
+		// the attempt returned at the same time
+		s.running.Add(-1)
+		res = <-done
+	}
+	if res.panicked {
+		panic(res.panic)
+	}
+	return res.err
+}
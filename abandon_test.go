@@ -0,0 +1,97 @@
+package backoff_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithAsyncAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	release := make(chan struct{})
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithPerTryTimeoutIntervals(backoff.IntervalsFunc(func(i int, last time.Duration) time.Duration {
+			return 10 * time.Millisecond
+		})),
+		backoff.WithAsyncAttempts())
+	start := time.Now()
+	err := bo.TryErr(ctx, 2, func(ctx context.Context) error {
+		// ignores ctx
+		<-release
+		return nil
+	})
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.True(t, errors.Is(err, backoff.AllTriesFailed))
+	assert.True(t, errors.Is(err, backoff.AttemptAbandoned))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, backoff.AsyncStats{Abandoned: 2, Running: 2}, bo.AsyncStats())
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return bo.AsyncStats().Running == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int64(2), bo.AsyncStats().Abandoned)
+}
+
+func Test_WithAsyncAttempts_Result(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bo := backoff.NewBackoff(shortInterval, backoff.WithAsyncAttempts())
+	assert.NoError(t, bo.TryErr(ctx, 3, failWith(assert.AnError)))
+	assert.Equal(t, backoff.AsyncStats{}, bo.AsyncStats())
+
+	bo = backoff.NewBackoff(shortInterval, backoff.WithAsyncAttempts(), backoff.WithRecoverPanics(backoff.PanicStop))
+	err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+		panic("boom")
+	})
+	var perr *backoff.PanicError
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, "boom", perr.Value)
+}
+
+func Test_WithOverrunWatchdog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	release := make(chan struct{})
+	overruns := make(chan backoff.Overrun, 2)
+	var buf bytes.Buffer
+	logOverrun := backoff.LogOverruns(log.New(&buf, "", 0))
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithPerTryTimeoutIntervals(backoff.IntervalsFunc(func(i int, last time.Duration) time.Duration {
+			return 10 * time.Millisecond
+		})),
+		backoff.WithOverrunWatchdog(func(o backoff.Overrun) {
+			logOverrun(o)
+			overruns <- o
+		}))
+	err := bo.TryErr(ctx, 1, func(ctx context.Context) error {
+		<-release
+		time.Sleep(20 * time.Millisecond)
+		return assert.AnError
+	})
+	assert.True(t, errors.Is(err, backoff.AttemptAbandoned))
+	close(release)
+
+	o := <-overruns
+	assert.Equal(t, 1, o.Number)
+	assert.NotEmpty(t, o.AttemptID)
+	assert.Equal(t, assert.AnError, o.Err)
+	assert.GreaterOrEqual(t, o.Overrun, 20*time.Millisecond)
+	assert.Contains(t, buf.String(), "attempt "+o.AttemptID+" returned")
+	stats := bo.AsyncStats()
+	assert.Equal(t, int64(1), stats.Abandoned)
+	assert.Zero(t, stats.Running)
+	assert.Equal(t, o.Overrun, stats.LongestOverrun)
+}
@@ -0,0 +1,68 @@
+package backoff
+
+import "context"
+
+// Future is the result of a retry loop running in its own goroutine. Use Done
+// to select on completion alongside other events.
+type Future struct {
+	done   chan struct{}
+	err    error
+	cancel context.CancelFunc
+}
+
+// Done returns a channel that is closed when the retry loop finishes.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Err returns the result of the retry loop once Done is closed. Before that
+// Err returns nil.
+func (f *Future) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the retry loop finishes and returns its result.
+func (f *Future) Wait() error {
+	<-f.done
+	return f.err
+}
+
+// Cancel cancels the context of the retry loop. The loop stops with
+// BackoffContextTimeoutExceeded unless it already finished.
+func (f *Future) Cancel() {
+	f.cancel()
+}
+
+// TryAsync runs Try in a new goroutine and returns a Future for its result.
+func (b *Backoff) TryAsync(ctx context.Context, tries int8, fn Completable) *Future {
+	return b.async(ctx, func(ctx context.Context) error {
+		return b.Try(ctx, tries, fn)
+	})
+}
+
+// TryErrAsync runs TryErr in a new goroutine and returns a Future for its
+// result.
+func (b *Backoff) TryErrAsync(ctx context.Context, tries int8, fn Operation) *Future {
+	return b.async(ctx, func(ctx context.Context) error {
+		return b.TryErr(ctx, tries, fn)
+	})
+}
+
+func (b *Backoff) async(ctx context.Context, loop func(ctx context.Context) error) *Future {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Future{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go func() {
+		defer cancel()
+		f.err = loop(ctx)
+		close(f.done)
+	}()
+	return f
+}
@@ -0,0 +1,60 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_TryAsync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval)
+
+	attempts := 0
+	f := bo.TryAsync(ctx, 5, func(ctx context.Context) bool {
+		attempts++
+		return attempts == 3
+	})
+
+	select {
+	case <-f.Done():
+	case <-ctx.Done():
+		t.Fatal("future did not complete")
+	}
+	assert.NoError(t, f.Err())
+	assert.NoError(t, f.Wait())
+	assert.Equal(t, 3, attempts)
+}
+
+func Test_TryErrAsync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval)
+
+	cause := errors.New("fail")
+	f := bo.TryErrAsync(ctx, 2, func(ctx context.Context) error {
+		return cause
+	})
+
+	err := f.Wait()
+	assert.True(t, errors.Is(err, backoff.AllTriesFailed))
+	assert.True(t, errors.Is(err, cause))
+}
+
+func Test_TryAsync_Cancel(t *testing.T) {
+	bo := backoff.NewBackoff(backoff.DefaultBinaryExponential())
+
+	f := bo.TryAsync(context.Background(), backoff.InfiniteTries, func(ctx context.Context) bool {
+		return false
+	})
+	assert.NoError(t, f.Err())
+
+	f.Cancel()
+	assert.Equal(t, backoff.BackoffContextTimeoutExceeded, f.Wait())
+}
@@ -0,0 +1,133 @@
+package backoff
+
+import (
+	"context"
+	crypto "crypto/rand"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Attempt describes the current attempt of a Try or TryErr call. It is
+// available to the Completable or Operation through ScheduleFromContext so the
+// callback can make smarter choices, for example skip an expensive cache
+// refresh on the last attempt.
+type Attempt struct {
+	// Number is the attempt number starting from 1
+	Number int
+	// RetryID identifies the Try or TryErr call and is shared by its attempts
+	RetryID string
+	// ID identifies the attempt. It is the RetryID and the attempt Number,
+	// like "4f1c2a9b0d3e7f60-2", for example to correlate the attempt with
+	// server logs in a request header.
+	ID string
+	// Remaining is the number of tries left after this attempt, -1 for
+	// InfiniteTries. With WithFailureWeights it assumes the following failures
+	// use a full try.
+	Remaining int
+
+	intervals Intervals
+	idx       int8
+	step      int       // position for an IntervalsV2
+	start     time.Time // start of the Try call for an IntervalsV2
+	deadline  time.Time // context deadline for a BudgetIntervals
+	started   time.Time // set after the attempt for the middleware
+	err       error
+	took      time.Duration
+	paused    bool // a backoff pause followed the attempt
+	last      time.Duration
+	burst     bool
+	once      sync.Once
+	wait      time.Duration
+}
+
+type attemptKey struct{}
+
+// ScheduleFromContext returns the Attempt of the Try call that called the
+// Completable or Operation with ctx.
+func ScheduleFromContext(ctx context.Context) (*Attempt, bool) {
+	a, ok := ctx.Value(attemptKey{}).(*Attempt)
+	return a, ok
+}
+
+// IsLast reports if this is the last attempt. No backoff pause follows a
+// failed last attempt.
+func (a *Attempt) IsLast() bool {
+	return a.Remaining == 0
+}
+
+// NextWait returns the planned backoff pause if this attempt fails, zero if
+// this is the last attempt. The pause is computed on the first call and reused
+// by the retry loop, so a stateful or random Intervals still produces a
+// consistent value.
+func (a *Attempt) NextWait() time.Duration {
+	if a.Remaining == 0 {
+		return 0
+	}
+	return a.nextWait()
+}
+
+func (a *Attempt) nextWait() time.Duration {
+	a.once.Do(func() {
+		if a.burst {
+			return
+		}
+		if iv := v2(a.intervals); iv != nil {
+			if bi, ok := iv.(BudgetIntervals); ok {
+				a.wait = bi.NextBudget(a.last, a.budget())
+				return
+			}
+			a.wait = iv.Next(a.step, a.last, time.Since(a.start))
+			return
+		}
+		a.wait = a.intervals.Next(a.idx, a.last)
+	})
+	return a.wait
+}
+
+func (b *Backoff) newAttempt(retryID string, attempt, offset int, tries int8, used float64, idx int8, step int, last time.Duration, start time.Time) *Attempt {
+	remaining := -1
+	if tries != InfiniteTries {
+		remaining = int(math.Ceil(float64(tries)-used)) - 1
+		if remaining < 0 {
+			remaining = 0
+		}
+	}
+	number := offset + attempt
+	return &Attempt{
+		Number:    number,
+		RetryID:   retryID,
+		ID:        retryID + "-" + strconv.Itoa(number),
+		Remaining: remaining,
+		intervals: b.intervals,
+		idx:       idx,
+		step:      b.v2Step(step, idx),
+		start:     start,
+		last:      last,
+		burst:     attempt < b.burst,
+	}
+}
+
+// AttemptIDFromContext returns the ID of the Attempt of ctx, or an empty string
+// if ctx is not the context of an attempt.
+func AttemptIDFromContext(ctx context.Context) string {
+	if a, ok := ScheduleFromContext(ctx); ok {
+		return a.ID
+	}
+	return ""
+}
+
+// newRetryID returns a random ID for a Try call
+func newRetryID() string {
+	var id [8]byte
+	if _, err := crypto.Read(id[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(id[:])
+}
+
+func withAttempt(ctx context.Context, a *Attempt) context.Context {
+	return context.WithValue(ctx, attemptKey{}, a)
+}
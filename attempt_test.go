@@ -0,0 +1,147 @@
+package backoff_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_ScheduleFromContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	type seen struct {
+		number    int
+		remaining int
+		nextWait  time.Duration
+	}
+	var got []seen
+	bo := backoff.NewBackoff(shortInterval)
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool {
+		a, ok := backoff.ScheduleFromContext(ctx)
+		require.True(t, ok)
+		got = append(got, seen{a.Number, a.Remaining, a.NextWait()})
+		return false
+	})
+
+	assert.Equal(t, backoff.AllTriesFailed, err)
+	assert.Equal(t, []seen{
+		{number: 1, remaining: 2, nextWait: 1 * time.Millisecond},
+		{number: 2, remaining: 1, nextWait: 2 * time.Millisecond},
+		{number: 3, remaining: 0, nextWait: 0},
+	}, got)
+}
+
+func Test_ScheduleFromContext_InfiniteTries(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	bo := backoff.NewBackoff(shortInterval)
+	err := bo.Try(ctx, backoff.InfiniteTries, func(ctx context.Context) bool {
+		a, ok := backoff.ScheduleFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, -1, a.Remaining)
+		return true
+	})
+	assert.NoError(t, err)
+}
+
+func Test_ScheduleFromContext_Missing(t *testing.T) {
+	_, ok := backoff.ScheduleFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+// returns a different interval on every call
+type counting struct {
+	calls int
+}
+
+func (c *counting) Next(i int8, last time.Duration) time.Duration {
+	c.calls++
+	return time.Duration(c.calls) * time.Millisecond
+}
+
+func Test_ScheduleFromContext_PlannedWaitIsUsed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	iv := &counting{}
+	rec := backoff.NewRecorder(10)
+	var planned []time.Duration
+	bo := backoff.NewBackoff(iv, backoff.WithRecorder(rec))
+	_ = bo.Try(ctx, 3, func(ctx context.Context) bool {
+		a, _ := backoff.ScheduleFromContext(ctx)
+		planned = append(planned, a.NextWait(), a.NextWait())
+		return false
+	})
+
+	records := rec.Snapshot()
+	require.Len(t, records, 3)
+	assert.Equal(t, []time.Duration{1 * time.Millisecond, 1 * time.Millisecond, 2 * time.Millisecond, 2 * time.Millisecond, 0, 0}, planned)
+	assert.Equal(t, 1*time.Millisecond, records[0].Wait)
+	assert.Equal(t, 2*time.Millisecond, records[1].Wait)
+	assert.Equal(t, 2, iv.calls)
+}
+
+func Test_Attempt_IsLast_NoPauseAfterLastAttempt(t *testing.T) {
+	for _, tries := range []int8{1, 2, 5} {
+		tries := tries
+		t.Run(fmt.Sprintf("%d tries", tries), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			var last []bool
+			bo := backoff.NewBackoff(shortInterval)
+			err := bo.TryErr(ctx, tries, func(ctx context.Context) error {
+				a, ok := backoff.ScheduleFromContext(ctx)
+				require.True(t, ok)
+				last = append(last, a.IsLast())
+				return assert.AnError
+			})
+
+			var retryErr *backoff.RetryError
+			require.ErrorAs(t, err, &retryErr)
+			assert.Equal(t, int(tries), retryErr.Attempts)
+			assert.Equal(t, int(tries)-1, retryErr.Pauses)
+			require.Len(t, last, int(tries))
+			for i, isLast := range last {
+				assert.Equal(t, i == len(last)-1, isLast)
+			}
+		})
+	}
+}
+
+func Test_AttemptIDs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var ids []string
+	var retryIDs []string
+	bo := backoff.NewBackoff(shortInterval)
+	for i := 0; i < 2; i++ {
+		err := bo.Try(ctx, 2, func(ctx context.Context) bool {
+			a, ok := backoff.ScheduleFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, a.ID, backoff.AttemptIDFromContext(ctx))
+			ids = append(ids, a.ID)
+			retryIDs = append(retryIDs, a.RetryID)
+			return false
+		})
+		assert.Equal(t, backoff.AllTriesFailed, err)
+	}
+
+	require.Len(t, ids, 4)
+	assert.Equal(t, retryIDs[0], retryIDs[1])
+	assert.NotEqual(t, retryIDs[0], retryIDs[2])
+	assert.Equal(t, retryIDs[0]+"-1", ids[0])
+	assert.Equal(t, retryIDs[0]+"-2", ids[1])
+	assert.Equal(t, retryIDs[2]+"-1", ids[2])
+	assert.Len(t, retryIDs[0], 16)
+	assert.Empty(t, backoff.AttemptIDFromContext(ctx))
+}
@@ -0,0 +1,103 @@
+package backoff
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// autoTuneMaxSamples bounds the number of latency samples kept by AutoTuner
+const autoTuneMaxSamples = 1024
+
+// AutoTuner is an Exponential series whose Initial interval follows the
+// latency of successful attempts. It samples the latencies and sets Initial to
+// a multiple of their 99th percentile, re-evaluated periodically, so the first
+// retry does not fire while a normal response could still be in flight.
+//
+// Feed it with WithAutoTuner or Observe. Until a latency is observed, the
+// Initial of the Exponential is used. An AutoTuner is safe for concurrent use.
+type AutoTuner struct {
+	exponential Exponential
+	multiple    float64
+	every       time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	samples   []time.Duration
+	next      int // ring buffer position once samples is full
+	initial   time.Duration
+	evaluated time.Time
+}
+
+var _ Intervals = (*AutoTuner)(nil)
+
+// NewAutoTuner creates a new AutoTuner for the series of e. Initial is set to
+// `multiple` times the 99th percentile of the observed latencies and
+// re-evaluated at most once per `every`.
+func NewAutoTuner(e Exponential, multiple float64, every time.Duration) *AutoTuner {
+	return &AutoTuner{
+		exponential: e,
+		multiple:    multiple,
+		every:       every,
+		now:         time.Now,
+		initial:     e.Initial,
+	}
+}
+
+// WithAutoTuner reports the latency of every successful attempt of the Backoff
+// to the AutoTuner. The AutoTuner is usually also the Intervals of the Backoff.
+func WithAutoTuner(at *AutoTuner) Options {
+	return func(bo *Backoff) {
+		bo.tuner = at
+	}
+}
+
+// Observe records the latency of a successful attempt.
+func (a *AutoTuner) Observe(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.samples) < autoTuneMaxSamples {
+		a.samples = append(a.samples, d)
+		return
+	}
+	a.samples[a.next] = d
+	a.next = (a.next + 1) % autoTuneMaxSamples
+}
+
+// Initial returns the current tuned Initial interval.
+func (a *AutoTuner) Initial() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if len(a.samples) > 0 && (a.evaluated.IsZero() || now.Sub(a.evaluated) >= a.every) {
+		sorted := make([]time.Duration, len(a.samples))
+		copy(sorted, a.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		a.initial = time.Duration(a.multiple * float64(percentile(sorted, 0.99)))
+		a.evaluated = now
+	}
+	return a.initial
+}
+
+// Next returns the interval of the Exponential series with the tuned Initial.
+func (a *AutoTuner) Next(i int8, last time.Duration) time.Duration {
+	e := a.exponential
+	e.Initial = a.Initial()
+	return e.Next(i, last)
+}
+
+// String describes the series, for example "exponential x2 auto-tuned to 3 x
+// p99 capped 20s".
+func (a *AutoTuner) String() string {
+	return fmt.Sprintf("exponential x%d auto-tuned to %g x p99 capped %s",
+		a.exponential.Base/a.exponential.Unit, a.multiple, a.exponential.Max)
+}
+
+// observe records a latency. It is safe to call on a nil AutoTuner.
+func (a *AutoTuner) observe(d time.Duration) {
+	if a == nil {
+		return
+	}
+	a.Observe(d)
+}
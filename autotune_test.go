@@ -0,0 +1,47 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AutoTuner(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	at := NewAutoTuner(DefaultBinaryExponential(), 3, time.Minute)
+	at.now = func() time.Time { return now }
+
+	// no samples yet
+	assert.Equal(t, 500*time.Millisecond, at.Next(0, 0))
+
+	for i := 1; i <= 100; i++ {
+		at.Observe(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, 297*time.Millisecond, at.Next(0, 0))
+	assert.Equal(t, 594*time.Millisecond, at.Next(1, 0))
+	assert.Equal(t, 20*time.Second, at.Next(10, 0))
+
+	// re-evaluated periodically
+	at.Observe(time.Second)
+	assert.Equal(t, 297*time.Millisecond, at.Next(0, 0))
+	now = now.Add(time.Minute)
+	assert.Equal(t, 300*time.Millisecond, at.Next(0, 0))
+
+	assert.Equal(t, "exponential x2 auto-tuned to 3 x p99 capped 20s", Describe(at))
+}
+
+func Test_WithAutoTuner(t *testing.T) {
+	at := NewAutoTuner(DefaultBinaryExponential(), 2, time.Minute)
+	bo := NewBackoff(at, withAfterFunc(immediateAfterFunc), WithAutoTuner(at))
+
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		time.Sleep(20 * time.Millisecond)
+		return true
+	})
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, at.Initial(), 40*time.Millisecond)
+	assert.Less(t, at.Initial(), 500*time.Millisecond)
+}
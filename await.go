@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// AwaitFunc polls a condition. It returns the current value, true when the
+// condition is met, or an error to stop polling.
+type AwaitFunc[T any] func(ctx context.Context) (T, bool, error)
+
+// Await polls fn until it reports done, pausing between polls with the series
+// of iv. This suits workflows like "wait until the cloud resource is ACTIVE"
+// where not being done yet is not a failure.
+//
+// If fn returns an error, Await stops immediately and returns the value and the
+// error. If the context is done first, Await returns the last polled value and
+// BackoffContextTimeoutExceeded.
+func Await[T any](ctx context.Context, iv Intervals, fn AwaitFunc[T]) (T, error) {
+	var (
+		wait time.Duration
+		i    int8
+	)
+	for {
+		v, done, err := fn(ctx)
+		if err != nil {
+			return v, err
+		}
+		if done {
+			return v, nil
+		}
+		wait = iv.Next(i, wait)
+		if Sleep(ctx, wait) != nil {
+			return v, BackoffContextTimeoutExceeded
+		}
+		if i < InfiniteTries {
+			i++
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Await(t *testing.T) {
+	statusErr := errors.New("resource failed")
+
+	cases := map[string]struct {
+		statuses  []string
+		timeout   time.Duration
+		want      string
+		wantErr   error
+		wantPolls int
+	}{
+		"Done immediately": {
+			statuses:  []string{"ACTIVE"},
+			timeout:   time.Second,
+			want:      "ACTIVE",
+			wantPolls: 1,
+		},
+		"Done after polling": {
+			statuses:  []string{"PENDING", "CREATING", "ACTIVE"},
+			timeout:   time.Second,
+			want:      "ACTIVE",
+			wantPolls: 3,
+		},
+		"Stop on error": {
+			statuses:  []string{"PENDING", "FAILED"},
+			timeout:   time.Second,
+			want:      "FAILED",
+			wantErr:   statusErr,
+			wantPolls: 2,
+		},
+		"Context timeout returns last value": {
+			statuses:  []string{"PENDING"},
+			timeout:   30 * time.Millisecond,
+			want:      "PENDING",
+			wantErr:   backoff.BackoffContextTimeoutExceeded,
+			wantPolls: -1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			polls := 0
+			got, err := backoff.Await(ctx, shortInterval, func(ctx context.Context) (string, bool, error) {
+				status := tc.statuses[len(tc.statuses)-1]
+				if polls < len(tc.statuses) {
+					status = tc.statuses[polls]
+				}
+				polls++
+				switch status {
+				case "ACTIVE":
+					return status, true, nil
+				case "FAILED":
+					return status, false, statusErr
+				}
+				return status, false, nil
+			})
+
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantPolls >= 0 {
+				assert.Equal(t, tc.wantPolls, polls)
+			}
+		})
+	}
+}
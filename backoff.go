@@ -3,6 +3,7 @@ package backoff
 import (
 	"context"
 	crypto "crypto/rand"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
@@ -26,12 +27,60 @@ const (
 	// BackoffContextTimeoutExceeded indicates that the backoff context Done
 	// channel was closed
 	BackoffContextTimeoutExceeded = Error("backoff context timeout exceeded")
+	// AttemptFailed is recorded for each Completable call that returned false
+	AttemptFailed = Error("attempt failed")
+	// PermanentFailure indicates that an attempt failed with a permanent error
+	// so no further tries were made
+	PermanentFailure = Error("permanent failure")
+	// RetryAborted indicates that the function provided with WithBeforeRetry
+	// stopped the loop
+	RetryAborted = Error("retry aborted")
+	// GateClosed is recorded for each attempt that the function provided with
+	// WithAttemptGate did not allow
+	GateClosed = Error("attempt gate closed")
 )
 
 // Completable is a function that should complete and terminate early if the
 // context.Done() channel is closed.
 type Completable func(ctx context.Context) bool
 
+// operation adapts a Completable to an Operation. A false return is reported
+// as AttemptFailed. A nil Completable gives a nil Operation.
+func (fn Completable) operation() Operation {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		if fn(ctx) {
+			return nil
+		}
+		return AttemptFailed
+	}
+}
+
+// StoppableCompletable is a Completable that can also request that no further
+// tries are made by returning stop true.
+type StoppableCompletable func(ctx context.Context) (ok bool, stop bool)
+
+// operation adapts a StoppableCompletable to an Operation. A stop is reported
+// as a Permanent AttemptFailed. A nil StoppableCompletable gives a nil
+// Operation.
+func (fn StoppableCompletable) operation() Operation {
+	if fn == nil {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		ok, stop := fn(ctx)
+		switch {
+		case ok:
+			return nil
+		case stop:
+			return Permanent(AttemptFailed)
+		}
+		return AttemptFailed
+	}
+}
+
 // after represents time.After method signature
 // this should only be used for testing
 type after func(time.Duration) <-chan time.Time
@@ -40,9 +89,7 @@ func defaultAfterFunc(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
 
-// Options are additional options to be used in NewBackoff. Currently
-// there are no exported options, only options that are used internally for
-// testing.
+// Options are additional options to be used in NewBackoff.
 type Options func(bo *Backoff)
 
 // only for testing
@@ -52,12 +99,191 @@ func withAfterFunc(fn after) Options {
 	}
 }
 
+// WithPerTryTimeoutIntervals limits each Completable call with a timeout that
+// follows the provided Intervals series. This lets early attempts fail fast
+// while later attempts get more time. For example the DefaultBinaryExponential
+// series gives the first call 0.5s, the second call 1s, and so on.
+//
+// When Try gives up the context of the last attempt is cancelled with the
+// error Try returns as its cause, so work that outlives the attempt sees for
+// example AllTriesFailed from context.Cause instead of a bare
+// context.Canceled.
+func WithPerTryTimeoutIntervals(iv Intervals) Options {
+	return func(bo *Backoff) {
+		bo.perTryTimeout = iv
+	}
+}
+
+// WithMaxIntervalIndex caps the iteration `i` passed to Intervals.Next at n
+// so the series stays at the n-th interval instead of continuing up to the
+// Intervals maximum.
+func WithMaxIntervalIndex(n int8) Options {
+	return func(bo *Backoff) {
+		bo.maxIndex = n
+		bo.wrapIndex = false
+	}
+}
+
+// WithIntervalIndexWrap makes the iteration `i` passed to Intervals.Next wrap
+// back to `to` after reaching n, so the series cycles. With InfiniteTries this
+// allows periodic probing at a slow interval with occasional fast probes.
+func WithIntervalIndexWrap(n, to int8) Options {
+	return func(bo *Backoff) {
+		bo.maxIndex = n
+		bo.wrapIndex = true
+		bo.wrapTo = to
+	}
+}
+
+// WithBeforeRetry calls fn after a failed attempt when another attempt will be
+// made, before the backoff pause. `attempt` is the number of the failed attempt
+// starting from 1. This is useful for cleanup or compensation, like rolling
+// back a partially created resource before trying to create it again. If fn
+// returns an error, the loop stops with RetryAborted.
+func WithBeforeRetry(fn func(ctx context.Context, attempt int) error) Options {
+	return func(bo *Backoff) {
+		bo.beforeRetry = fn
+	}
+}
+
+// WithCheckContextBeforeAttempt makes the loop check the context before each
+// attempt, including the first one, and stop with BackoffContextTimeoutExceeded
+// if it is done. Without it an attempt that races with the end of a backoff
+// pause may still be made after the context is done.
+func WithCheckContextBeforeAttempt(check bool) Options {
+	return func(bo *Backoff) {
+		bo.checkContext = check
+	}
+}
+
+// WithAlwaysAttemptOnce guarantees that the function is called at least once.
+// If the context is already done when Try is called, the function is called
+// exactly once with a context that keeps the values of the original context
+// but is never cancelled. This is useful for cleanup operations that must
+// always be attempted.
+func WithAlwaysAttemptOnce() Options {
+	return func(bo *Backoff) {
+		bo.attemptOnce = true
+	}
+}
+
+// WithDetachedAttempts runs each attempt with a context that keeps the values
+// of the Try context but is never cancelled, so an attempt in flight is not
+// interrupted. The Try context still stops the loop between attempts. Combine
+// with WithPerTryTimeoutIntervals to bound each attempt.
+func WithDetachedAttempts() Options {
+	return func(bo *Backoff) {
+		bo.detachAttempts = true
+	}
+}
+
+// WithAttemptWatchdog calls onStuck when a single attempt runs longer than d.
+// The attempt is not cancelled, this only gives visibility into stuck attempts
+// that silently use up the retry budget. onStuck runs in its own goroutine. A
+// nil onStuck disables the watchdog.
+func WithAttemptWatchdog(d time.Duration, onStuck func()) Options {
+	return func(bo *Backoff) {
+		bo.watchdog = d
+		bo.onStuck = onStuck
+	}
+}
+
+// WithStopChannel stops the loop when stop is closed, for code that uses done
+// channels rather than contexts. Closing stop has the same effect as cancelling
+// the context: the context passed to the attempt is cancelled and Try returns
+// BackoffContextTimeoutExceeded.
+func WithStopChannel(stop <-chan struct{}) Options {
+	return func(bo *Backoff) {
+		bo.stop = stop
+	}
+}
+
+// WithAttemptGate calls gate before each attempt, for example to take a
+// distributed lock so only one instance across a fleet performs an expensive
+// global operation. If gate returns proceed false, the attempt is not made and
+// fails with GateClosed, so the loop backs off and asks the gate again. Use
+// WithClassifier and WithFreeClass to not consume tries while the gate is
+// closed. Otherwise release, if not nil, is called once the attempt finished.
+func WithAttemptGate(gate func(ctx context.Context) (proceed bool, release func())) Options {
+	return func(bo *Backoff) {
+		bo.gate = gate
+	}
+}
+
+// WithDeadline stops the loop at the absolute time t, as if the context had the
+// deadline t, for when the context intentionally has no deadline but business
+// rules cap the retry window. The loop also gives up right away when the next
+// attempt would start after t instead of pausing until then.
+func WithDeadline(t time.Time) Options {
+	return func(bo *Backoff) {
+		bo.deadline = t
+	}
+}
+
+// WithBurst makes the first n attempts back-to-back without a pause. The
+// Intervals series starts after the n-th attempt. One immediate retry usually
+// succeeds after a transient loss of a single packet.
+func WithBurst(n int) Options {
+	return func(bo *Backoff) {
+		bo.burst = n
+	}
+}
+
 // Backoff is a simple backoff implementation. You will want to use NewBackoff
 // or NewBackoffWithTimeout to create an instance.
 type Backoff struct {
 	intervals Intervals
 	afterFunc after
 	result    chan bool
+
+	perTryTimeout  Intervals
+	onGiveUp       func(GiveUpInfo)
+	collectErrors  bool
+	recoverPanics  bool
+	panicPolicy    PanicPolicy
+	storm          *StormDetector
+	chaos          *chaos
+	maxIndex       int8
+	wrapIndex      bool
+	wrapTo         int8
+	hints          HintProvider
+	beforeRetry    func(ctx context.Context, attempt int) error
+	classifier     func(err error) Class
+	freeClasses    map[Class]bool
+	classIntervals map[Class]Intervals
+	limiter        *ConcurrencyLimiter
+	checkContext   bool
+	attemptOnce    bool
+	detachAttempts bool
+	watchdog       time.Duration
+	onStuck        func()
+	stop           <-chan struct{}
+	traceName      string
+	profileName    string
+	deadlineSplit  []float64
+	gate           func(ctx context.Context) (proceed bool, release func())
+	deadline       time.Time
+	burst          int
+	weights        map[Class]float64
+	tuner          *AutoTuner
+	scopeSetup     func(ctx context.Context) (cleanup func(), err error)
+	scopeReset     map[Class]bool
+	reauth         *Reauthorizer
+	onNested       func(depth, amplification int)
+	nestedFlatten  bool
+	nestedCap      int
+	clockAudit     *clockAudit
+	zeroTries      ZeroTriesPolicy
+	repeatLimit    int
+	middleware     middlewares
+	shutdown       *shutdown
+	drain          *drain
+	numbering      AttemptNumbering
+	attemptsMade   int
+	asyncAttempts  *asyncAttempts
+
+	// set on a copy of the Backoff while a Loop is running
+	loop *Loop
 }
 
 // NewBackoff creates a new Backoff struct. Intervals represents the interval
@@ -70,6 +296,9 @@ func NewBackoff(intervals Intervals, options ...Options) *Backoff {
 		intervals: intervals,
 		afterFunc: defaultAfterFunc,
 		result:    make(chan bool, 1),
+		maxIndex:  InfiniteTries,
+		shutdown:  newShutdown(),
+		drain:     newDrain(),
 	}
 	for _, option := range options {
 		option(backoff)
@@ -82,36 +311,340 @@ func NewBackoff(intervals Intervals, options ...Options) *Backoff {
 //
 // If the Completable returns false more times than specified in tries in
 // NewBackoff, then Try will return a AllTriesFailed error.
+// Try returns as soon as the last attempt fails, without a backoff pause.
 //
 // If the provided context cancel function is called before a Completable call
 // returns true, then Try will return a BackoffContextTimeoutExceeded error.
-func (b *Backoff) Try(ctx context.Context, tries int8, fn Completable) error {
-	return b.try(ctx, tries, fn, 0, 0)
+//
+// Invalid arguments are reported without calling fn: a nil ctx returns
+// NilContext, a nil fn returns NilOperation, negative tries return
+// NegativeTries and a Backoff without Intervals returns NilIntervals. Zero
+// tries make a single attempt unless changed with WithZeroTries.
+//
+// Options apply to this call only, for example UseIntervals.
+func (b *Backoff) Try(ctx context.Context, tries int8, fn Completable, options ...Options) error {
+	return b.with(options).try(ctx, tries, fn, 0, 0)
+}
+
+// UseIntervals substitutes the Intervals series for a single Try or TryErr
+// call:
+//
+//	err := bo.Try(ctx, 3, fn, backoff.UseIntervals(backoff.Immediate(0).Intervals))
+func UseIntervals(iv Intervals) Options {
+	return func(bo *Backoff) {
+		bo.intervals = iv
+	}
+}
+
+// with returns a copy of b with the options applied, or b without options
+func (b *Backoff) with(options []Options) *Backoff {
+	if len(options) == 0 {
+		return b
+	}
+	c := *b
+	// options may write to the maps and slices, keep the ones of b intact
+	c.freeClasses = cloneMap(b.freeClasses)
+	c.classIntervals = cloneMap(b.classIntervals)
+	c.weights = cloneMap(b.weights)
+	c.scopeReset = cloneMap(b.scopeReset)
+	c.deadlineSplit = append([]float64(nil), b.deadlineSplit...)
+	c.middleware = append(middlewares(nil), b.middleware...)
+	for _, option := range options {
+		option(&c)
+	}
+	return &c
+}
+
+// cloneMap returns a copy of m, nil for a nil m
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	c := make(map[K]V, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// TryStoppable is like Try but the StoppableCompletable can stop the loop
+// immediately, in which case TryStoppable returns PermanentFailure. This is
+// useful for users of the bool based API that cannot move to TryErr yet.
+func (b *Backoff) TryStoppable(ctx context.Context, tries int8, fn StoppableCompletable) error {
+	err, _, h := b.run(ctx, tries, fn.operation(), 0, 0)
+	if err != nil {
+		b.giveUp(h, err)
+	}
+	return err
 }
 
 // Specify initI and initWait to start the loop at a pre-determined point in the
 // series. The assumed starting point is initI = 0, initWait = 0.
 func (b *Backoff) try(ctx context.Context, tries int8, fn Completable, initI int8, initWait time.Duration) error {
+	err, _, h := b.run(ctx, tries, fn.operation(), initI, initWait)
+	if err != nil {
+		b.giveUp(h, err)
+	}
+	return err
+}
+
+// run is the retry loop shared by Try and TryErr. It returns the terminal error
+// (nil on success), the error of the last attempt and the recorded history.
+func (b *Backoff) run(ctx context.Context, tries int8, fn Operation, initI int8, initWait time.Duration) (err error, lastErr error, h *history) {
+	if err := validate(ctx, tries, fn); err != nil {
+		return err, nil, b.newHistory()
+	}
+	b, tries = b.withContextPolicy(ctx, tries)
+	if b.intervals == nil {
+		return NilIntervals, nil, b.newHistory()
+	}
+	tries, err = b.withZeroTries(tries)
+	if err != nil {
+		return err, nil, b.newHistory()
+	}
+	ctx, tries = b.nest(ctx, tries)
+	offset := b.attemptOffset(initI) // see WithAttemptNumbering
+	ctx, endTask := b.traceTask(ctx)
+	defer endTask()
+	all := allShutdown.Load() // see ShutdownAll
+	ctx, releaseShutdown := b.withShutdown(ctx, all)
+	defer releaseShutdown()
+	if b.stop != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = withStop(ctx, b.stop)
+		defer cancel()
+	}
+	if !b.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, b.deadline)
+		defer cancel()
+	}
 	wait := initWait
-	i := initI
+	used := float64(initI) // try credits used, see WithFailureWeights
+	idx := initI           // index into the Intervals series
+	step := int(initI)     // position in an IntervalsV2 series
+	var timeout time.Duration
+	attempt := 0
+	defer func() {
+		b.loop.addAttempts(attempt)
+	}()
+	h = b.newHistory()
+	var classes map[Class]*series
+	budget := b.splitBudget(ctx)
+	reauthorized := false // the last attempt followed a refresh
+	var repeated repeats
+	retryID := newRetryID()
+	var a *Attempt
+	defer func() {
+		b.middleware.onFinish(ctx, a, err)
+	}()
+	sc := b.newScope()
+	defer sc.close()
+	fn = sc.wrap(fn)
+	cancelAttempt := func(error) {}
+	defer func() {
+		cancelAttempt(err)
+	}()
 	for {
-		if fn(ctx) {
-			return nil
+		cancelAttempt(nil)
+		attemptCtx := ctx
+		if b.detachAttempts {
+			attemptCtx = context.WithoutCancel(ctx)
+		}
+		if attempt == 0 && b.attemptOnce && ctx.Err() != nil {
+			attemptCtx = context.WithoutCancel(ctx)
+		} else {
+			if err := b.loop.waitResumed(ctx); err != nil {
+				return BackoffContextTimeoutExceeded, lastErr, h
+			}
+			if b.checkContext && ctx.Err() != nil {
+				return BackoffContextTimeoutExceeded, lastErr, h
+			}
+		}
+		if !b.enterAttempt(all) {
+			return ShutDown, lastErr, h
+		}
+		attempt++
+		number := offset + attempt
+		a = b.newAttempt(retryID, attempt, offset, tries, used, idx, step, wait, h.start)
+		a.deadline, _ = ctx.Deadline()
+		attemptCtx, cancelSplit := b.splitDeadline(attemptCtx, budget, attempt)
+		attemptCtx, cancelAttempt = b.attemptContext(attemptCtx, idx, &timeout)
+		attemptCtx = b.middleware.beforeAttempt(withAttempt(attemptCtx, a), a)
+		start := time.Now()
+		lastErr = b.chaos.inject(b.call(attemptCtx, all, fn))
+		cancelSplit()
+		took := time.Since(start)
+		a.started, a.err, a.took = start, lastErr, took
+		b.middleware.afterAttempt(attemptCtx, a, lastErr, took)
+		if lastErr == nil {
+			b.limiter.adjust(1)
+			b.tuner.observe(took)
+			return nil, nil, h
+		}
+		h.attempt(lastErr, took)
+		if permErr, ok := isPermanent(lastErr); ok {
+			return PermanentFailure, permErr, h
+		}
+		if b.repeatLimit > 0 && repeated.seen(lastErr) >= b.repeatLimit {
+			return RepeatedError, lastErr, h
+		}
+		class := b.classify(lastErr)
+		if b.scopeReset[class] {
+			sc.close()
+		}
+		if b.reauth != nil && class == b.reauth.Class && !reauthorized {
+			if err := b.reauth.refresh(ctx); err != nil {
+				return RetryAborted, err, h
+			}
+			reauthorized = true
+			// retry without a pause
+			a.paused = true
+			b.middleware.beforeSleep(ctx, a, 0)
+			continue
+		}
+		reauthorized = false
+		free := b.freeClasses[class]
+		if !free {
+			used += b.weight(class)
+		}
+		if !free && used >= float64(tries) && InfiniteTries != tries {
+			return AllTriesFailed, lastErr, h
+		}
+		if b.shuttingDown(all) {
+			return ShutDown, lastErr, h
+		}
+		if b.drain.active() {
+			return Draining, lastErr, h
+		}
+		if ctx.Err() != nil {
+			// do not compute a wait that can race with ctx.Done
+			return BackoffContextTimeoutExceeded, lastErr, h
+		}
+		if b.beforeRetry != nil {
+			if err := b.beforeRetry(ctx, number); err != nil {
+				return RetryAborted, err, h
+			}
+		}
+		var next time.Duration
+		if iv := b.classIntervals[class]; iv != nil {
+			next, classes = classNext(classes, class, iv)
+		} else {
+			wait = a.nextWait()
+			next = wait
+		}
+		pause := b.storm.stretchWait(b.hint(lastErr, next))
+		b.limiter.adjust(-1)
+		if !b.deadline.IsZero() && time.Now().Add(pause).After(b.deadline) {
+			// the next attempt would start after the deadline
+			return BackoffContextTimeoutExceeded, lastErr, h
+		}
+		h.wait(pause)
+		a.paused = true
+		b.middleware.beforeSleep(ctx, a, pause)
+		if pause > 0 {
+			mark := b.clockAudit.begin()
+			chWait := b.afterFunc(pause)
+			select {
+			case <-ctx.Done():
+				return BackoffContextTimeoutExceeded, lastErr, h
+			case <-chWait:
+				b.clockAudit.end(mark, number, pause)
+			case <-b.loop.retryNow():
+			case <-b.shutdown.drained():
+				return ShutDown, lastErr, h
+			case <-all.drained():
+				return ShutDown, lastErr, h
+			case <-b.drain.started():
+				return Draining, lastErr, h
+			}
+		} else if ctx.Err() != nil {
+			// fast path without a timer for zero waits
+			return BackoffContextTimeoutExceeded, lastErr, h
+		}
+		// repeat the loop
+		if free {
+			// free failures do not consume a try
+			continue
 		}
-		if i+1 >= tries && InfiniteTries != tries {
-			return AllTriesFailed
+		if attempt >= b.burst {
+			idx = b.nextIndex(idx)
+			step++
 		}
-		wait = b.intervals.Next(i, wait)
-		chWait := b.afterFunc(wait)
+	}
+}
+
+// withStop returns a copy of ctx that is cancelled when stop is closed
+func withStop(ctx context.Context, stop <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
 		select {
+		case <-stop:
+			cancel()
 		case <-ctx.Done():
-			return BackoffContextTimeoutExceeded
-		case <-chWait:
-			// repeat the loop
-			if i < InfiniteTries {
-				i++
-			}
 		}
+	}()
+	return ctx, cancel
+}
+
+// nextIndex advances the index into the Intervals series
+func (b *Backoff) nextIndex(idx int8) int8 {
+	if idx < b.maxIndex {
+		return idx + 1
+	}
+	if b.wrapIndex {
+		return b.wrapTo
+	}
+	return b.maxIndex
+}
+
+// call runs a single attempt of fn. The attempt must be registered with
+// enterAttempt, call unregisters it even if fn panics.
+func (b *Backoff) call(ctx context.Context, all *shutdown, fn Operation) (err error) {
+	defer b.leaveAttempt(all)
+	if b.gate != nil {
+		proceed, release := b.gate(ctx)
+		if !proceed {
+			return GateClosed
+		}
+		if release != nil {
+			defer release()
+		}
+	}
+	if b.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				err = b.panicError(r)
+			}
+		}()
+	}
+	if b.watchdog > 0 && b.onStuck != nil {
+		stuck := time.AfterFunc(b.watchdog, b.onStuck)
+		defer stuck.Stop()
+	}
+	if b.profileName != "" {
+		fn = b.labeled(fn)
+	}
+	if b.asyncAttempts != nil {
+		return b.asyncAttempts.call(ctx, fn)
+	}
+	return fn(ctx)
+}
+
+// attemptContext derives the context of an attempt when a per try timeout is
+// set. The loop keeps the context until the next attempt or until Try returns
+// and cancels it with the error Try returns as the cause, so an operation that
+// outlives the attempt can tell from context.Cause that the retries gave up.
+func (b *Backoff) attemptContext(ctx context.Context, i int8, timeout *time.Duration) (context.Context, func(cause error)) {
+	if b.perTryTimeout == nil {
+		return ctx, func(error) {}
+	}
+	*timeout = b.perTryTimeout.Next(i, *timeout)
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	return ctx, func(cause error) {
+		cancelCause(cause)
+		cancel()
 	}
 }
 
@@ -121,10 +654,30 @@ func (b *Backoff) try(ctx context.Context, tries int8, fn Completable, initI int
 // iteration. The number of iterations is expected to be fairly small, but if
 // the number of iterations is InfiniteTries (math.MaxInt8), `i` will always be
 // InfiniteTries.
+//
+// New strategies should implement IntervalsV2, which is not limited to int8
+// and also receives the elapsed time.
 type Intervals interface {
 	Next(i int8, last time.Duration) time.Duration
 }
 
+// IntervalsFunc adapts an ordinary function to Intervals, for one-off
+// schedules that do not warrant a new type:
+//
+//	iv := backoff.IntervalsFunc(func(i int, last time.Duration) time.Duration {
+//		return time.Duration(i+1) * time.Second
+//	})
+//
+// The function receives `i` as an int.
+type IntervalsFunc func(i int, last time.Duration) time.Duration
+
+var _ Intervals = IntervalsFunc(nil)
+
+// Next calls f(int(i), last).
+func (f IntervalsFunc) Next(i int8, last time.Duration) time.Duration {
+	return f(int(i), last)
+}
+
 // Exponential implements an exponential interval function.
 type Exponential struct {
 	Base    time.Duration
@@ -164,12 +717,24 @@ func (e Exponential) Next(i int8, last time.Duration) time.Duration {
 	return time.Duration(next)
 }
 
+// String describes the series, for example "exponential x2 from 500ms capped
+// 20s".
+func (e Exponential) String() string {
+	return fmt.Sprintf("exponential x%d from %s capped %s", e.Base/e.Unit, e.Initial, e.Max)
+}
+
 // ExponentialJitter implements an exponential interval function with a
 // random jitter factor added to each fixed interval.
+//
+// The jitter is either absolute, a random value between +/- JitterMax, or
+// relative with RandomizationFactor f, a random value in [interval*(1-f),
+// interval*(1+f)] as in other common retry libraries. If both are set, the
+// absolute jitter is added to the randomized interval.
 type ExponentialJitter struct {
 	Exponential
-	JitterMax time.Duration
-	Rand      *rand.Rand
+	JitterMax           time.Duration
+	RandomizationFactor float64
+	Rand                *rand.Rand
 }
 
 // generates a new *rand.Rand with a cryptographically random seed
@@ -205,8 +770,29 @@ func DefaultBinaryExponentialJitter() (ExponentialJitter, error) {
 // method contains jitter and it is seeded by crypto/rand it will return
 // seemingly non-deterministic random values.
 func (ej ExponentialJitter) Next(i int8, last time.Duration) time.Duration {
+	next := ej.Exponential.Next(i, last)
+	if ej.RandomizationFactor > 0 {
+		delta := ej.RandomizationFactor * float64(next)
+		next = time.Duration(float64(next) - delta + ej.Rand.Float64()*2*delta)
+	}
+	if ej.JitterMax <= 0 {
+		return next
+	}
 	randRange := ej.JitterMax * 2
 	// center at 0
 	jitter := ej.Rand.Int63n(int64(randRange)) - int64(ej.JitterMax)
-	return ej.Exponential.Next(i, last) + time.Duration(jitter)
+	return next + time.Duration(jitter)
+}
+
+// String describes the series, for example "exponential x2 from 500ms capped
+// 20s ±500ms jitter".
+func (ej ExponentialJitter) String() string {
+	s := ej.Exponential.String()
+	if ej.RandomizationFactor > 0 {
+		s += fmt.Sprintf(" ±%g%% randomization", ej.RandomizationFactor*100)
+	}
+	if ej.JitterMax > 0 {
+		s += fmt.Sprintf(" ±%s jitter", ej.JitterMax)
+	}
+	return s
 }
@@ -2,6 +2,8 @@ package backoff_test
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -87,3 +89,224 @@ func Test_Try(t *testing.T) {
 		})
 	}
 }
+
+func Test_Try_AttemptWatchdog(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var stuck int32
+	bo := backoff.NewBackoff(shortInterval, backoff.WithAttemptWatchdog(10*time.Millisecond, func() {
+		atomic.AddInt32(&stuck, 1)
+	}))
+	calls := 0
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool {
+		calls++
+		if calls == 2 {
+			// the second attempt is stuck but finishes without being cancelled
+			time.Sleep(50 * time.Millisecond)
+			return ctx.Err() == nil
+		}
+		return false
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stuck))
+}
+
+func Test_Try_AttemptWatchdog_NilCallback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval, backoff.WithAttemptWatchdog(time.Millisecond, nil))
+	err := bo.Try(ctx, 1, func(ctx context.Context) bool {
+		time.Sleep(20 * time.Millisecond)
+		return true
+	})
+	assert.NoError(t, err)
+}
+
+func Test_Try_StopChannel(t *testing.T) {
+	cases := map[string]struct {
+		stopDuringAttempt bool
+		wantCalls         int
+	}{
+		"Stop during the pause": {
+			wantCalls: 1,
+		},
+		"Stop during an attempt": {
+			stopDuringAttempt: true,
+			wantCalls:         1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			stop := make(chan struct{})
+			bo := backoff.NewBackoff(backoff.DefaultBinaryExponential(), backoff.WithStopChannel(stop))
+			calls := 0
+			err := bo.Try(context.Background(), 5, func(ctx context.Context) bool {
+				calls++
+				if tc.stopDuringAttempt {
+					close(stop)
+					<-ctx.Done()
+					return false
+				}
+				time.AfterFunc(10*time.Millisecond, func() { close(stop) })
+				return false
+			})
+
+			assert.Equal(t, backoff.BackoffContextTimeoutExceeded, err)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func Test_TryStoppable(t *testing.T) {
+	cases := map[string]struct {
+		results   [][2]bool // ok, stop
+		wantErr   error
+		wantCalls int
+	}{
+		"Succeed": {
+			results:   [][2]bool{{false, false}, {true, false}},
+			wantCalls: 2,
+		},
+		"Stop": {
+			results:   [][2]bool{{false, false}, {false, true}, {true, false}},
+			wantErr:   backoff.PermanentFailure,
+			wantCalls: 2,
+		},
+		"All tries fail": {
+			results:   [][2]bool{{false, false}, {false, false}, {false, false}},
+			wantErr:   backoff.AllTriesFailed,
+			wantCalls: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			calls := 0
+			bo := backoff.NewBackoff(shortInterval)
+			err := bo.TryStoppable(ctx, 3, func(ctx context.Context) (bool, bool) {
+				r := tc.results[calls]
+				calls++
+				return r[0], r[1]
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func Test_Try_AttemptGate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var events []string
+	gates := []bool{false, true, true}
+	bo := backoff.NewBackoff(shortInterval, backoff.WithAttemptGate(func(ctx context.Context) (bool, func()) {
+		proceed := gates[0]
+		gates = gates[1:]
+		events = append(events, fmt.Sprintf("gate:%t", proceed))
+		return proceed, func() { events = append(events, "release") }
+	}))
+	err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+		events = append(events, "attempt")
+		if len(gates) > 0 {
+			return assert.AnError
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"gate:false",
+		"gate:true", "attempt", "release",
+		"gate:true", "attempt", "release",
+	}, events)
+}
+
+func Test_Try_WithDeadline(t *testing.T) {
+	cases := map[string]struct {
+		interval  backoff.Intervals
+		deadline  time.Duration
+		wantCalls int
+		maxTook   time.Duration
+	}{
+		"Deadline during attempts": {
+			interval:  backoff.Immediate(0).Intervals,
+			deadline:  50 * time.Millisecond,
+			wantCalls: 3,
+			maxTook:   200 * time.Millisecond,
+		},
+		"Give up before a pause past the deadline": {
+			interval:  backoff.DefaultBinaryExponential(),
+			deadline:  100 * time.Millisecond,
+			wantCalls: 1,
+			maxTook:   50 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			start := time.Now()
+			bo := backoff.NewBackoff(tc.interval, backoff.WithDeadline(start.Add(tc.deadline)))
+			calls := 0
+			err := bo.Try(context.Background(), backoff.InfiniteTries, func(ctx context.Context) bool {
+				calls++
+				<-time.After(20 * time.Millisecond)
+				return false
+			})
+
+			assert.Equal(t, backoff.BackoffContextTimeoutExceeded, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Less(t, time.Since(start), tc.maxTook)
+		})
+	}
+}
+
+func Test_Try_UseIntervals(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rec := backoff.NewRecorder(10)
+	bo := backoff.NewBackoff(backoff.DefaultBinaryExponential(), backoff.WithRecorder(rec))
+
+	calls := 0
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool {
+		calls++
+		return calls == 3
+	}, backoff.UseIntervals(shortInterval))
+	assert.NoError(t, err)
+
+	err = bo.TryErr(ctx, 2, failWith(assert.AnError), backoff.UseIntervals(backoff.Immediate(0).Intervals))
+	assert.NoError(t, err)
+
+	var waits []time.Duration
+	for _, r := range rec.Snapshot() {
+		waits = append(waits, r.Wait)
+	}
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 0, 0, 0}, waits)
+}
+
+func Test_IntervalsFunc(t *testing.T) {
+	iv := backoff.IntervalsFunc(func(i int, last time.Duration) time.Duration {
+		return last + time.Duration(i+1)*time.Millisecond
+	})
+
+	var got []time.Duration
+	for _, e := range backoff.Schedule(iv, 4) {
+		got = append(got, e.Interval)
+	}
+	assert.Equal(t, []time.Duration{time.Millisecond, 3 * time.Millisecond, 6 * time.Millisecond, 10 * time.Millisecond}, got)
+
+	calls := 0
+	err := backoff.NewBackoff(iv).Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return calls == 3
+	})
+	assert.NoError(t, err)
+}
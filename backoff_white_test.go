@@ -138,10 +138,8 @@ func Test_try(t *testing.T) {
 				Max:     0 * time.Millisecond,
 			},
 			wantErr: BackoffContextTimeoutExceeded,
-			wantDurations: []time.Duration{
-				0 * time.Millisecond,
-				0 * time.Millisecond,
-			},
+			// zero waits do not use the after func
+			wantDurations: nil,
 			wantEvents: []string{
 				try.CaseAfter,
 				try.CaseReturnFalse,
@@ -368,3 +366,286 @@ func Test_DefaultBinaryExponentialJitter_RandomInputNextShouldBeWithinRange(t *t
 			i, last, got, minWant, maxWant)
 	}
 }
+
+func Test_ExponentialJitter_RandomizationFactor(t *testing.T) {
+	ej := ExponentialJitter{
+		Exponential:         DefaultBinaryExponential(),
+		RandomizationFactor: 0.5,
+		Rand:                rand.New(rand.NewSource(1)),
+	}
+
+	for iteration := 0; iteration < 1000; iteration++ {
+		i := int8(rand.Intn(8))
+		interval := ej.Exponential.Next(i, 0)
+		got := ej.Next(i, 0)
+
+		assert.True(t, interval/2 <= got && got <= interval*3/2,
+			"Next(%d) got %s is not in range %s and %s", i, got, interval/2, interval*3/2)
+	}
+	assert.Equal(t, "exponential x2 from 500ms capped 20s ±50% randomization", ej.String())
+}
+
+// an after func that fires immediately so tests do not wait on the backoff
+func immediateAfterFunc(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- time.Now()
+	return ch
+}
+
+func Test_try_PerTryTimeoutIntervals(t *testing.T) {
+	timeouts := Exponential{
+		Base:    2 * time.Millisecond,
+		Unit:    time.Millisecond,
+		Initial: 10 * time.Millisecond,
+		Max:     40 * time.Millisecond,
+	}
+	var elapsed []time.Duration
+	fn := func(ctx context.Context) bool {
+		start := time.Now()
+		<-ctx.Done()
+		elapsed = append(elapsed, time.Since(start))
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := NewBackoff(DefaultBinaryExponential(),
+		withAfterFunc(immediateAfterFunc),
+		WithPerTryTimeoutIntervals(timeouts),
+	)
+	err := bo.Try(ctx, 4, fn)
+
+	assert.Equal(t, AllTriesFailed, err)
+	require.Len(t, elapsed, 4)
+	for i, want := range []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		40 * time.Millisecond,
+	} {
+		assert.True(t, elapsed[i] >= want && elapsed[i] < want+200*time.Millisecond,
+			"attempt %d took %s, want about %s", i, elapsed[i], want)
+	}
+}
+
+func Test_try_PerTryTimeoutCause(t *testing.T) {
+	cases := map[string]struct {
+		errs      []error
+		wantErr   error
+		wantCause error
+	}{
+		"Success": {
+			errs:      []error{AttemptFailed},
+			wantCause: context.Canceled,
+		},
+		"All tries failed": {
+			errs:      []error{AttemptFailed, AttemptFailed, AttemptFailed},
+			wantErr:   AllTriesFailed,
+			wantCause: AllTriesFailed,
+		},
+		"Permanent failure": {
+			errs:      []error{Permanent(AttemptFailed)},
+			wantErr:   PermanentFailure,
+			wantCause: PermanentFailure,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var contexts []context.Context
+			calls := 0
+			bo := NewBackoff(DefaultBinaryExponential(),
+				withAfterFunc(immediateAfterFunc),
+				WithPerTryTimeoutIntervals(DefaultBinaryExponential()),
+			)
+			err := bo.TryErr(context.Background(), 3, func(ctx context.Context) error {
+				contexts = append(contexts, ctx)
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			})
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+			for _, ctx := range contexts[:len(contexts)-1] {
+				assert.Equal(t, context.Canceled, context.Cause(ctx))
+			}
+			assert.Equal(t, tc.wantCause, context.Cause(contexts[len(contexts)-1]))
+		})
+	}
+}
+
+func Test_try_IntervalIndex(t *testing.T) {
+	cases := map[string]struct {
+		option Options
+		want   []time.Duration
+	}{
+		"Default saturates at Max": {
+			option: func(bo *Backoff) {},
+			want: []time.Duration{
+				500 * time.Millisecond, 1 * time.Second, 2 * time.Second,
+				4 * time.Second, 8 * time.Second, 16 * time.Second,
+				20 * time.Second, 20 * time.Second,
+			},
+		},
+		"Max index": {
+			option: WithMaxIntervalIndex(2),
+			want: []time.Duration{
+				500 * time.Millisecond, 1 * time.Second, 2 * time.Second,
+				2 * time.Second, 2 * time.Second, 2 * time.Second,
+				2 * time.Second, 2 * time.Second,
+			},
+		},
+		"Wrap index": {
+			option: WithIntervalIndexWrap(3, 1),
+			want: []time.Duration{
+				500 * time.Millisecond, 1 * time.Second, 2 * time.Second,
+				4 * time.Second, 1 * time.Second, 2 * time.Second,
+				4 * time.Second, 1 * time.Second,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var durations []time.Duration
+			afterFn := func(d time.Duration) <-chan time.Time {
+				durations = append(durations, d)
+				return immediateAfterFunc(d)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(afterFn), tc.option)
+			err := bo.Try(ctx, InfiniteTries, func(ctx context.Context) bool {
+				return len(durations) == len(tc.want)
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, durations)
+		})
+	}
+}
+
+func Test_try_CheckContextBeforeAttempt(t *testing.T) {
+	cases := map[string]struct {
+		check         bool
+		cancelUpfront bool
+		wantCalls     int
+	}{
+		"Cancelled during pause": {
+			check:     true,
+			wantCalls: 1,
+		},
+		"Cancelled before the first attempt": {
+			check:         true,
+			cancelUpfront: true,
+			wantCalls:     0,
+		},
+		"Cancelled before the first attempt without check": {
+			check:         false,
+			cancelUpfront: true,
+			wantCalls:     1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelUpfront {
+				cancel()
+			}
+			// the pause ends at the same time the context is cancelled
+			afterFn := func(d time.Duration) <-chan time.Time {
+				cancel()
+				return immediateAfterFunc(d)
+			}
+			calls := 0
+			bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(afterFn), WithCheckContextBeforeAttempt(tc.check))
+			err := bo.Try(ctx, 10, func(ctx context.Context) bool {
+				calls++
+				return false
+			})
+
+			assert.Equal(t, BackoffContextTimeoutExceeded, err)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func Test_try_AlwaysAttemptOnce(t *testing.T) {
+	type key struct{}
+	cases := map[string]struct {
+		succeed bool
+		wantErr error
+	}{
+		"Attempt succeeds": {
+			succeed: true,
+		},
+		"Attempt fails": {
+			succeed: false,
+			wantErr: BackoffContextTimeoutExceeded,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+			cancel()
+			calls := 0
+			bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc),
+				WithCheckContextBeforeAttempt(true), WithAlwaysAttemptOnce())
+			err := bo.Try(ctx, 10, func(ctx context.Context) bool {
+				calls++
+				assert.NoError(t, ctx.Err())
+				assert.Equal(t, "value", ctx.Value(key{}))
+				return tc.succeed
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func Test_try_DetachedAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc), WithDetachedAttempts())
+	err := bo.Try(ctx, 10, func(attemptCtx context.Context) bool {
+		calls++
+		cancel()
+		// the attempt is not interrupted by the cancelled loop
+		assert.NoError(t, attemptCtx.Err())
+		return false
+	})
+
+	assert.Equal(t, BackoffContextTimeoutExceeded, err)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_try_Burst(t *testing.T) {
+	var durations []time.Duration
+	afterFn := func(d time.Duration) <-chan time.Time {
+		durations = append(durations, d)
+		return immediateAfterFunc(d)
+	}
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(afterFn), WithBurst(3))
+	err := bo.Try(context.Background(), 6, func(ctx context.Context) bool {
+		return false
+	})
+
+	assert.Equal(t, AllTriesFailed, err)
+	// the burst pauses are zero and do not use the after func
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}, durations)
+}
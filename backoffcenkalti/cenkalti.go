@@ -0,0 +1,82 @@
+// Package backoffcenkalti adapts backoff Intervals to and from the BackOff
+// interface of github.com/cenkalti/backoff so projects can migrate between the
+// libraries while reusing existing policy definitions.
+//
+// The adapters satisfy the interface structurally and do not import the
+// library, so using this package does not add dependencies.
+package backoffcenkalti
+
+import (
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// Stop is returned by NextBackOff to indicate no more retries should be made.
+// It has the same value as backoff.Stop in github.com/cenkalti/backoff.
+const Stop time.Duration = -1
+
+// BackOff is a copy of the cenkalti BackOff interface. Any cenkalti policy,
+// such as *backoff.ExponentialBackOff, satisfies it.
+type BackOff interface {
+	NextBackOff() time.Duration
+	Reset()
+}
+
+// FromCenkaltiBackOff returns Intervals that take their values from b. b is
+// reset when a new series starts. When b returns Stop the previous interval is
+// repeated, so bound the attempts with `tries`.
+//
+// Like the cenkalti policies the result keeps state and must not be shared by
+// concurrent Try calls.
+func FromCenkaltiBackOff(b BackOff) backoff.Intervals {
+	return &fromCenkalti{b: b}
+}
+
+type fromCenkalti struct {
+	b BackOff
+}
+
+func (f *fromCenkalti) Next(i int8, last time.Duration) time.Duration {
+	if i == 0 {
+		f.b.Reset()
+	}
+	d := f.b.NextBackOff()
+	if d == Stop {
+		return last
+	}
+	return d
+}
+
+// CenkaltiBackOff implements the cenkalti BackOff interface on top of
+// Intervals. Create it with ToCenkaltiBackOff.
+type CenkaltiBackOff struct {
+	intervals backoff.Intervals
+
+	i    int8
+	last time.Duration
+}
+
+var _ BackOff = (*CenkaltiBackOff)(nil)
+
+// ToCenkaltiBackOff returns a cenkalti BackOff producing the intervals of iv.
+// It never returns Stop; wrap it with the cenkalti WithMaxRetries to limit the
+// retries.
+func ToCenkaltiBackOff(iv backoff.Intervals) *CenkaltiBackOff {
+	return &CenkaltiBackOff{intervals: iv}
+}
+
+// NextBackOff returns the next interval of the series.
+func (c *CenkaltiBackOff) NextBackOff() time.Duration {
+	c.last = c.intervals.Next(c.i, c.last)
+	if c.i < backoff.InfiniteTries {
+		c.i++
+	}
+	return c.last
+}
+
+// Reset restarts the series from the first interval.
+func (c *CenkaltiBackOff) Reset() {
+	c.i = 0
+	c.last = 0
+}
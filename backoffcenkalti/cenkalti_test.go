@@ -0,0 +1,84 @@
+package backoffcenkalti_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffcenkalti"
+)
+
+// constantBackOff mimics a cenkalti policy that stops after n intervals.
+type constantBackOff struct {
+	d      time.Duration
+	n      int
+	calls  int
+	resets int
+}
+
+func (c *constantBackOff) NextBackOff() time.Duration {
+	c.calls++
+	if c.calls > c.n {
+		return backoffcenkalti.Stop
+	}
+	return c.d * time.Duration(c.calls)
+}
+
+func (c *constantBackOff) Reset() {
+	c.calls = 0
+	c.resets++
+}
+
+func Test_FromCenkaltiBackOff(t *testing.T) {
+	b := &constantBackOff{d: time.Second, n: 3}
+	iv := backoffcenkalti.FromCenkaltiBackOff(b)
+
+	cases := map[string]struct {
+		n    int
+		want []time.Duration
+	}{
+		"Follows the policy": {
+			n:    3,
+			want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second},
+		},
+		"Repeats the last interval after Stop": {
+			n:    5,
+			want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var got []time.Duration
+			for _, e := range backoff.Schedule(iv, tc.n) {
+				got = append(got, e.Interval)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+	assert.Equal(t, 2, b.resets)
+}
+
+func Test_FromCenkaltiBackOff_Try(t *testing.T) {
+	iv := backoffcenkalti.FromCenkaltiBackOff(&constantBackOff{d: time.Millisecond, n: 10})
+	calls := 0
+	err := backoff.NewBackoff(iv).Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return calls == 3
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func Test_ToCenkaltiBackOff(t *testing.T) {
+	b := backoffcenkalti.ToCenkaltiBackOff(backoff.DefaultBinaryExponential())
+	assert.Equal(t, 500*time.Millisecond, b.NextBackOff())
+	assert.Equal(t, time.Second, b.NextBackOff())
+	assert.Equal(t, 2*time.Second, b.NextBackOff())
+	b.Reset()
+	assert.Equal(t, 500*time.Millisecond, b.NextBackOff())
+}
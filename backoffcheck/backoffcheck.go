@@ -0,0 +1,110 @@
+// Package backoffcheck verifies invariants of backoff.Intervals
+// implementations. It is meant to be used in tests of custom Intervals.
+package backoffcheck
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// Config describes the expected shape of a series.
+type Config struct {
+	// Max is the largest interval of the series without jitter
+	Max time.Duration
+	// Jitter is the largest absolute jitter added to an interval
+	Jitter time.Duration
+	// ConvergeWithin is the iteration at which the series must have reached
+	// Max. Zero skips the check.
+	ConvergeWithin int8
+	// MaxCount is the number of random inputs to check. Defaults to 1000.
+	MaxCount int
+	// Seed seeds the random inputs. Zero uses a time based seed.
+	Seed int64
+}
+
+// Check verifies that iv satisfies the following invariants and returns an
+// error describing the first violation:
+//
+//   - intervals are never negative
+//   - intervals are bounded by Max+Jitter for any `i` and `last`
+//   - the series is non-decreasing up to Max, within the jitter
+//   - the series reaches Max within ConvergeWithin iterations
+//
+// The bounds are checked with random inputs using testing/quick.
+func Check(iv backoff.Intervals, cfg Config) error {
+	if err := checkBounds(iv, cfg); err != nil {
+		return err
+	}
+	return checkSeries(iv, cfg)
+}
+
+// Verify calls Check and reports a violation as a test error.
+func Verify(t testing.TB, iv backoff.Intervals, cfg Config) {
+	t.Helper()
+	if err := Check(iv, cfg); err != nil {
+		t.Error(err)
+	}
+}
+
+func checkBounds(iv backoff.Intervals, cfg Config) error {
+	upper := cfg.Max + cfg.Jitter
+	var violation error
+	property := func(i int8, last int64) bool {
+		if i < 0 {
+			i = -(i + 1)
+		}
+		l := time.Duration(last)
+		if l < 0 {
+			l = -(l + 1)
+		}
+		l %= upper + 1
+		got := iv.Next(i, l)
+		if got < 0 {
+			violation = fmt.Errorf("Next(%d, %s) = %s is negative", i, l, got)
+			return false
+		}
+		if got > upper {
+			violation = fmt.Errorf("Next(%d, %s) = %s is above %s", i, l, got, upper)
+			return false
+		}
+		return true
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	maxCount := cfg.MaxCount
+	if maxCount == 0 {
+		maxCount = 1000
+	}
+	err := quick.Check(property, &quick.Config{
+		MaxCount: maxCount,
+		Rand:     rand.New(rand.NewSource(seed)),
+	})
+	if err != nil && violation != nil {
+		return violation
+	}
+	return err
+}
+
+func checkSeries(iv backoff.Intervals, cfg Config) error {
+	var last time.Duration
+	for i := 0; i <= math.MaxInt8; i++ {
+		next := iv.Next(int8(i), last)
+		if i > 0 && next < last-2*cfg.Jitter && last < cfg.Max-cfg.Jitter {
+			return fmt.Errorf("Next(%d, %s) = %s decreases before reaching %s", i, last, next, cfg.Max)
+		}
+		if cfg.ConvergeWithin > 0 && i >= int(cfg.ConvergeWithin) && next < cfg.Max-cfg.Jitter {
+			return fmt.Errorf("Next(%d, %s) = %s has not reached %s within %d iterations",
+				i, last, next, cfg.Max, cfg.ConvergeWithin)
+		}
+		last = next
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package backoffcheck_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffcheck"
+)
+
+type constant time.Duration
+
+func (c constant) Next(i int8, last time.Duration) time.Duration {
+	return time.Duration(c)
+}
+
+type decreasing struct{}
+
+func (d decreasing) Next(i int8, last time.Duration) time.Duration {
+	if i >= 10 {
+		return 0
+	}
+	return time.Duration(10-i) * time.Second
+}
+
+func Test_Check(t *testing.T) {
+	dbej, err := backoff.DefaultBinaryExponentialJitter()
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		iv      backoff.Intervals
+		cfg     backoffcheck.Config
+		wantErr bool
+	}{
+		"DefaultBinaryExponential": {
+			iv:  backoff.DefaultBinaryExponential(),
+			cfg: backoffcheck.Config{Max: 20 * time.Second, ConvergeWithin: 6},
+		},
+		"DefaultBinaryExponentialJitter": {
+			iv: dbej,
+			cfg: backoffcheck.Config{
+				Max:            20 * time.Second,
+				Jitter:         500 * time.Millisecond,
+				ConvergeWithin: 6,
+			},
+		},
+		"Above max": {
+			iv:      constant(time.Minute),
+			cfg:     backoffcheck.Config{Max: time.Second},
+			wantErr: true,
+		},
+		"Negative": {
+			iv:      constant(-time.Second),
+			cfg:     backoffcheck.Config{Max: time.Second},
+			wantErr: true,
+		},
+		"Does not converge": {
+			iv:      backoff.DefaultBinaryExponential(),
+			cfg:     backoffcheck.Config{Max: 20 * time.Second, ConvergeWithin: 3},
+			wantErr: true,
+		},
+		"Decreasing": {
+			iv:      decreasing{},
+			cfg:     backoffcheck.Config{Max: time.Hour},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			tc.cfg.Seed = 1
+			err := backoffcheck.Check(tc.iv, tc.cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Verify(t *testing.T) {
+	backoffcheck.Verify(t, backoff.DefaultBinaryExponential(), backoffcheck.Config{
+		Max:            20 * time.Second,
+		ConvergeWithin: 6,
+	})
+}
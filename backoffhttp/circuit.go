@@ -0,0 +1,146 @@
+package backoffhttp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// ErrCircuitOpen is returned for requests to a host whose circuit is open.
+const ErrCircuitOpen = backoff.Error("backoffhttp: circuit open for host")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// HostCircuit tracks the failure rate of attempts per destination host. When
+// the failure rate of a host exceeds the threshold, its circuit opens and
+// requests to the host fail fast with ErrCircuitOpen instead of being retried.
+// After ProbeAfter a single request is let through as a probe: if it succeeds
+// the circuit closes, otherwise it stays open for another ProbeAfter.
+//
+// Set it as Transport.Circuit. A HostCircuit created with a struct literal is
+// ready to use like one created with NewHostCircuit. A HostCircuit is safe for
+// concurrent use and can be shared between Transports.
+type HostCircuit struct {
+	// Threshold is the failure ratio (0 to 1) of attempts that opens the
+	// circuit
+	Threshold float64
+	// MinAttempts is the number of attempts in a window required before the
+	// circuit can open
+	MinAttempts int
+	// Window is the period over which the failure ratio is measured
+	Window time.Duration
+	// ProbeAfter is how long the circuit stays open before a probe
+	ProbeAfter time.Duration
+
+	now   func() time.Time
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+}
+
+type hostCircuit struct {
+	state       circuitState
+	windowStart time.Time
+	attempts    int
+	failures    int
+	openedAt    time.Time
+}
+
+// NewHostCircuit creates a new HostCircuit.
+func NewHostCircuit(threshold float64, minAttempts int, window, probeAfter time.Duration) *HostCircuit {
+	return &HostCircuit{
+		Threshold:   threshold,
+		MinAttempts: minAttempts,
+		Window:      window,
+		ProbeAfter:  probeAfter,
+		now:         time.Now,
+		hosts:       make(map[string]*hostCircuit),
+	}
+}
+
+// Open reports if the circuit of host is open.
+func (c *HostCircuit) Open(host string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := c.hosts[host]
+	return ok && h.state != circuitClosed
+}
+
+// allow reports if an attempt to host may be made. When the circuit is open
+// and ProbeAfter has passed, the attempt becomes the probe.
+func (c *HostCircuit) allow(host string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.host(host)
+	switch h.state {
+	case circuitOpen:
+		if c.clock().Sub(h.openedAt) < c.ProbeAfter {
+			return false
+		}
+		h.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// a probe is in flight
+		return false
+	}
+	return true
+}
+
+// record records the outcome of an attempt to host and reports if the circuit
+// is still closed.
+func (c *HostCircuit) record(host string, failed bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.host(host)
+	now := c.clock()
+	if h.state == circuitHalfOpen {
+		if failed {
+			h.state = circuitOpen
+			h.openedAt = now
+			return false
+		}
+		*h = hostCircuit{windowStart: now}
+		return true
+	}
+	if now.Sub(h.windowStart) >= c.Window {
+		h.windowStart = now
+		h.attempts = 0
+		h.failures = 0
+	}
+	h.attempts++
+	if failed {
+		h.failures++
+	}
+	if h.attempts >= c.MinAttempts && float64(h.failures)/float64(h.attempts) > c.Threshold {
+		h.state = circuitOpen
+		h.openedAt = now
+	}
+	return h.state == circuitClosed
+}
+
+// host returns the state of host. c.mu must be held.
+func (c *HostCircuit) host(host string) *hostCircuit {
+	h, ok := c.hosts[host]
+	if !ok {
+		if c.hosts == nil {
+			c.hosts = make(map[string]*hostCircuit)
+		}
+		h = &hostCircuit{windowStart: c.clock()}
+		c.hosts[host] = h
+	}
+	return h
+}
+
+// clock returns the current time. c.mu must be held.
+func (c *HostCircuit) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
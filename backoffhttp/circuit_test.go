@@ -0,0 +1,63 @@
+package backoffhttp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HostCircuit(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	c := NewHostCircuit(0.5, 4, time.Minute, 10*time.Second)
+	c.now = func() time.Time { return now }
+
+	// not enough attempts to open
+	assert.True(t, c.record("a", true))
+	assert.True(t, c.record("a", true))
+	assert.True(t, c.record("a", false))
+	assert.True(t, c.allow("a"))
+
+	// 3 of 4 failed
+	assert.False(t, c.record("a", true))
+	assert.True(t, c.Open("a"))
+	assert.False(t, c.allow("a"))
+
+	// other hosts are not affected
+	assert.True(t, c.allow("b"))
+	assert.False(t, c.Open("b"))
+
+	// a failed probe keeps the circuit open
+	now = now.Add(10 * time.Second)
+	assert.True(t, c.allow("a"))
+	assert.False(t, c.allow("a"), "only one probe at a time")
+	assert.False(t, c.record("a", true))
+	assert.False(t, c.allow("a"))
+
+	// a successful probe closes the circuit
+	now = now.Add(10 * time.Second)
+	assert.True(t, c.allow("a"))
+	assert.True(t, c.record("a", false))
+	assert.False(t, c.Open("a"))
+	assert.True(t, c.allow("a"))
+}
+
+func Test_HostCircuit_WindowResets(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	c := NewHostCircuit(0.5, 2, time.Minute, 10*time.Second)
+	c.now = func() time.Time { return now }
+
+	assert.True(t, c.record("a", true))
+	now = now.Add(time.Minute)
+	assert.True(t, c.record("a", true))
+	assert.False(t, c.Open("a"))
+}
+
+func Test_HostCircuit_StructLiteral(t *testing.T) {
+	c := &HostCircuit{Threshold: 0.5, MinAttempts: 1, Window: time.Minute, ProbeAfter: time.Minute}
+	assert.False(t, c.Open("a"))
+	assert.True(t, c.allow("a"))
+	assert.False(t, c.record("a", true))
+	assert.True(t, c.Open("a"))
+	assert.False(t, c.allow("a"))
+}
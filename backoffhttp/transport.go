@@ -0,0 +1,279 @@
+// Package backoffhttp retries net/http client requests with the backoff
+// package.
+package backoffhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+const (
+	// DefaultMaxBufferedBody is the default limit of request body bytes
+	// buffered so the body can be sent again on retries
+	DefaultMaxBufferedBody = 1 << 20
+
+	// maxDrain is the most response body bytes read before closing a response
+	// that is retried. Draining lets the connection be reused.
+	maxDrain = 64 << 10
+
+	// retryableStatus is the error of an attempt with a retryable response
+	retryableStatus = backoff.Error("retryable response status")
+)
+
+// AttemptInfo describes a single attempt of a request.
+type AttemptInfo struct {
+	// Attempt is the attempt number starting from 1
+	Attempt int
+	// Reused reports if the connection was reused from a previous request
+	Reused bool
+	// WasIdle reports if the reused connection was idle
+	WasIdle bool
+	// StatusCode is the response status, zero if there was no response
+	StatusCode int
+	// Err is the transport error, if any
+	Err error
+	// Duration is how long the attempt took
+	Duration time.Duration
+}
+
+// Transport is an http.RoundTripper that retries requests with a Backoff.
+//
+// Request bodies are rewound between attempts with Request.GetBody when it is
+// set, otherwise bodies up to MaxBufferedBody bytes are buffered. A request
+// with a larger body is sent only once. A retried response body is drained
+// and closed before the next attempt so the connection can be reused.
+//
+// A Retry-After header on a retryable response is used as the next pause.
+//
+// If all tries fail with a retryable response, the last response is returned
+// with a nil error like http.RoundTripper expects.
+type Transport struct {
+	// Base is the RoundTripper used for each attempt. Defaults to
+	// http.DefaultTransport.
+	Base http.RoundTripper
+	// Backoff computes the pauses between attempts. Defaults to a Backoff with
+	// the Intervals of backoff.PresetAPIClient.
+	Backoff *backoff.Backoff
+	// Tries is the maximum number of attempts per request
+	Tries int8
+	// Retryable reports if an attempt should be retried. Defaults to
+	// DefaultRetryable.
+	Retryable func(resp *http.Response, err error) bool
+	// MaxBufferedBody is the largest request body buffered for retries.
+	// Defaults to DefaultMaxBufferedBody.
+	MaxBufferedBody int64
+	// OnAttempt is called after each attempt. It may be nil.
+	OnAttempt func(*http.Request, AttemptInfo)
+	// Circuit stops retrying to hosts with a high failure rate. It may be nil.
+	Circuit *HostCircuit
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// DefaultRetryable retries transport errors, unless the request context is
+// done, and responses with status 429 Too Many Requests or 5xx except 501 Not
+// Implemented.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	getBody, ok, err := t.rewinder(req)
+	if err != nil {
+		return nil, err
+	}
+	tries := t.Tries
+	if !ok {
+		// the body is too large to rewind
+		tries = 1
+	}
+
+	var (
+		resp    *http.Response
+		lastErr error
+		attempt int
+	)
+	err = t.bo().TryErr(req.Context(), tries, func(ctx context.Context) error {
+		attempt++
+		if resp != nil {
+			drain(resp)
+			resp = nil
+		}
+		if t.Circuit != nil && !t.Circuit.allow(req.URL.Host) {
+			lastErr = ErrCircuitOpen
+			return backoff.Permanent(lastErr)
+		}
+		var info AttemptInfo
+		resp, lastErr = t.attempt(ctx, req, getBody, &info)
+		info.Attempt = attempt
+		if t.OnAttempt != nil {
+			t.OnAttempt(req, info)
+		}
+		return t.classify(req.URL.Host, resp, lastErr)
+	})
+	switch {
+	case err == nil:
+		return resp, nil
+	case lastErr == nil && resp != nil && !errors.Is(err, backoff.BackoffContextTimeoutExceeded):
+		// out of tries or the circuit opened with a retryable status
+		return resp, nil
+	}
+	if resp != nil {
+		drain(resp)
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, req.Context().Err()
+}
+
+// attempt sends a copy of req with a fresh body and records connection reuse
+// in info
+func (t *Transport) attempt(ctx context.Context, req *http.Request, getBody func() (io.ReadCloser, error), info *AttemptInfo) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(ci httptrace.GotConnInfo) {
+			info.Reused = ci.Reused
+			info.WasIdle = ci.WasIdle
+		},
+	}
+	r := req.Clone(httptrace.WithClientTrace(ctx, trace))
+	if getBody != nil {
+		body, err := getBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	start := time.Now()
+	resp, err := t.base().RoundTrip(r)
+	info.Duration = time.Since(start)
+	info.Err = err
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	return resp, err
+}
+
+// rewinder returns a function that returns a new copy of the request body for
+// each attempt. It returns false if the body is too large to be rewound, in
+// which case the returned function returns the original body once.
+func (t *Transport) rewinder(req *http.Request) (func() (io.ReadCloser, error), bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true, nil
+	}
+	if req.GetBody != nil {
+		// every attempt gets a body from GetBody, the original is not sent
+		req.Body.Close()
+		return req.GetBody, true, nil
+	}
+	limit := t.MaxBufferedBody
+	if limit <= 0 {
+		limit = DefaultMaxBufferedBody
+	}
+	buf, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		req.Body.Close()
+		return nil, false, err
+	}
+	if int64(len(buf)) > limit {
+		body := struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+		return func() (io.ReadCloser, error) {
+			return body, nil
+		}, false, nil
+	}
+	req.Body.Close()
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf)), nil
+	}, true, nil
+}
+
+// defaultBackoff is the Backoff of a Transport without one
+var defaultBackoff = backoff.PresetAPIClient().NewBackoff()
+
+func (t *Transport) bo() *backoff.Backoff {
+	if t.Backoff == nil {
+		return defaultBackoff
+	}
+	return t.Backoff
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// classify returns the error reported to TryErr for an attempt and records the
+// outcome in the circuit
+func (t *Transport) classify(host string, resp *http.Response, err error) error {
+	retry := t.retryable(resp, err)
+	closed := true
+	if t.Circuit != nil {
+		closed = t.Circuit.record(host, retry)
+	}
+	if !retry {
+		return backoff.Permanent(err)
+	}
+	if err == nil {
+		err = retryableStatus
+		if d, ok := retryAfter(resp); ok {
+			err = backoff.RetryAfter(err, d)
+		}
+	}
+	if !closed {
+		// fail fast with the last result
+		return backoff.Permanent(err)
+	}
+	return err
+}
+
+func (t *Transport) retryable(resp *http.Response, err error) bool {
+	if t.Retryable == nil {
+		return DefaultRetryable(resp, err)
+	}
+	return t.Retryable(resp, err)
+}
+
+// retryAfter parses the Retry-After header of resp in seconds or as an HTTP
+// date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// drain reads a limited amount of the response body and closes it so the
+// connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrain)
+	resp.Body.Close()
+}
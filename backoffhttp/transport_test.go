@@ -0,0 +1,271 @@
+package backoffhttp_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffhttp"
+)
+
+var shortInterval = backoff.Exponential{
+	Base:    2 * time.Millisecond,
+	Unit:    time.Millisecond,
+	Initial: 1 * time.Millisecond,
+	Max:     20 * time.Millisecond,
+}
+
+// a server that responds with the statuses in order and records request bodies
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *[]string) {
+	var (
+		mu     sync.Mutex
+		i      int
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		status := statuses[len(statuses)-1]
+		if i < len(statuses) {
+			status = statuses[i]
+		}
+		i++
+		mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func Test_Transport(t *testing.T) {
+	cases := map[string]struct {
+		statuses     []int
+		tries        int8
+		wantStatus   int
+		wantAttempts int
+	}{
+		"Succeed Immediately": {
+			statuses:     []int{200},
+			tries:        3,
+			wantStatus:   200,
+			wantAttempts: 1,
+		},
+		"Succeed after retries": {
+			statuses:     []int{503, 429, 200},
+			tries:        3,
+			wantStatus:   200,
+			wantAttempts: 3,
+		},
+		"Return last response when out of tries": {
+			statuses:     []int{503},
+			tries:        3,
+			wantStatus:   503,
+			wantAttempts: 3,
+		},
+		"Do not retry client errors": {
+			statuses:     []int{404},
+			tries:        3,
+			wantStatus:   404,
+			wantAttempts: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			srv, bodies := statusServer(t, tc.statuses...)
+			var infos []backoffhttp.AttemptInfo
+			client := &http.Client{Transport: &backoffhttp.Transport{
+				Backoff: backoff.NewBackoff(shortInterval),
+				Tries:   tc.tries,
+				OnAttempt: func(r *http.Request, info backoffhttp.AttemptInfo) {
+					infos = append(infos, info)
+				},
+			}}
+
+			resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("hello"))
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			assert.Len(t, body, 100)
+			assert.Len(t, *bodies, tc.wantAttempts)
+			for _, b := range *bodies {
+				assert.Equal(t, "hello", b)
+			}
+			require.Len(t, infos, tc.wantAttempts)
+			for i, info := range infos {
+				assert.Equal(t, i+1, info.Attempt)
+				// drained responses let later attempts reuse the connection
+				assert.Equal(t, i > 0, info.Reused)
+			}
+		})
+	}
+}
+
+type onlyReader struct {
+	io.Reader
+}
+
+func Test_Transport_LargeBodyIsNotRetried(t *testing.T) {
+	srv, bodies := statusServer(t, 503)
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff:         backoff.NewBackoff(shortInterval),
+		Tries:           3,
+		MaxBufferedBody: 4,
+	}}
+
+	// hide the type so net/http does not set GetBody
+	body := onlyReader{bytes.NewReader([]byte("hello"))}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Equal(t, []string{"hello"}, *bodies)
+}
+
+func Test_Transport_UnbufferedSmallBodyIsRetried(t *testing.T) {
+	srv, bodies := statusServer(t, 503, 200)
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   3,
+	}}
+
+	body := onlyReader{bytes.NewReader([]byte("hello"))}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, []string{"hello", "hello"}, *bodies)
+}
+
+// closeTracker records if the body was closed
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func Test_Transport_GetBodyClosesOriginalBody(t *testing.T) {
+	srv, bodies := statusServer(t, 503, 200)
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   3,
+	}}
+
+	body := &closeTracker{Reader: strings.NewReader("hello")}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, body)
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("hello")), nil
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, []string{"hello", "hello"}, *bodies)
+	assert.True(t, body.closed)
+}
+
+func Test_Transport_DefaultBackoff(t *testing.T) {
+	srv, _ := statusServer(t, 200)
+	client := &http.Client{Transport: &backoffhttp.Transport{Tries: 3}}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func Test_Transport_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	attempts := 0
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   3,
+		OnAttempt: func(r *http.Request, info backoffhttp.AttemptInfo) {
+			attempts++
+			assert.Error(t, info.Err)
+		},
+	}}
+
+	_, err := client.Get(url)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func Test_Transport_CircuitFailsFast(t *testing.T) {
+	srv, bodies := statusServer(t, 503)
+	circuit := backoffhttp.NewHostCircuit(0.5, 2, time.Minute, time.Minute)
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   5,
+		Circuit: circuit,
+	}}
+
+	// the circuit opens after the second failed attempt
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Len(t, *bodies, 2)
+
+	_, err = client.Get(srv.URL)
+	assert.True(t, errors.Is(err, backoffhttp.ErrCircuitOpen))
+	assert.Len(t, *bodies, 2)
+}
+
+func Test_Transport_RetryAfter(t *testing.T) {
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &backoffhttp.Transport{
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   3,
+	}}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	require.Len(t, times, 2)
+	assert.True(t, times[1].Sub(times[0]) >= time.Second)
+}
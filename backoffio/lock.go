@@ -0,0 +1,74 @@
+// Package backoffio retries contended I/O, like file locks and flaky network
+// file systems, with the backoff package.
+package backoffio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+
+	"github.com/rhomel/backoff"
+)
+
+// Retryable reports if err is a temporary I/O condition worth retrying. By
+// default EAGAIN (EWOULDBLOCK), EBUSY and EINTR are retryable.
+func Retryable(err error) bool {
+	return errors.Is(err, syscall.EAGAIN) ||
+		errors.Is(err, syscall.EBUSY) ||
+		errors.Is(err, syscall.EINTR)
+}
+
+// AcquireLock calls tryLock until it acquires the lock, pausing between tries
+// with the series of iv. tryLock should not block: it returns a release
+// function when the lock was acquired, or an error. Retryable errors are
+// retried until ctx is done, other errors are returned immediately as they
+// are, like with RetryOpen.
+func AcquireLock(ctx context.Context, tryLock func() (release func(), err error), iv backoff.Intervals) (func(), error) {
+	var release func()
+	var lockErr error
+	err := retry(ctx, iv, func() error {
+		release, lockErr = tryLock()
+		return lockErr
+	})
+	if err != nil {
+		if lockErr != nil && !Retryable(lockErr) {
+			return nil, lockErr
+		}
+		return nil, err
+	}
+	return release, nil
+}
+
+// RetryOpen is like os.OpenFile but retries retryable errors, pausing between
+// tries with the series of iv until ctx is done. Like os.OpenFile it returns
+// a *os.PathError, the error of the last try, so checks like os.IsNotExist
+// work. Only when ctx is done before the first try it returns the error of
+// the retry loop.
+func RetryOpen(ctx context.Context, name string, flag int, perm os.FileMode, iv backoff.Intervals) (*os.File, error) {
+	var f *os.File
+	var openErr error
+	err := retry(ctx, iv, func() error {
+		f, openErr = os.OpenFile(name, flag, perm)
+		return openErr
+	})
+	if err != nil {
+		if openErr != nil {
+			return nil, openErr
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// retry calls fn until it succeeds, fails with a non retryable error or ctx is
+// done
+func retry(ctx context.Context, iv backoff.Intervals, fn func() error) error {
+	return backoff.NewBackoff(iv).TryErr(ctx, backoff.InfiniteTries, func(ctx context.Context) error {
+		err := fn()
+		if err != nil && !Retryable(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	})
+}
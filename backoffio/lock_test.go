@@ -0,0 +1,121 @@
+package backoffio_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffio"
+)
+
+var shortInterval = backoff.Exponential{
+	Base:    2 * time.Millisecond,
+	Unit:    time.Millisecond,
+	Initial: 1 * time.Millisecond,
+	Max:     20 * time.Millisecond,
+}
+
+func Test_AcquireLock(t *testing.T) {
+	permErr := errors.New("permission denied")
+
+	cases := map[string]struct {
+		errs         []error
+		timeout      time.Duration
+		wantErr      error
+		wantAttempts int
+	}{
+		"Acquire immediately": {
+			timeout:      time.Second,
+			wantAttempts: 1,
+		},
+		"Acquire after contention": {
+			errs:         []error{syscall.EAGAIN, syscall.EBUSY},
+			timeout:      time.Second,
+			wantAttempts: 3,
+		},
+		"Stop on non retryable error": {
+			errs:         []error{syscall.EAGAIN, permErr},
+			timeout:      time.Second,
+			wantErr:      permErr,
+			wantAttempts: 2,
+		},
+		"Context timeout": {
+			errs:         []error{syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN},
+			timeout:      10 * time.Millisecond,
+			wantErr:      backoff.BackoffContextTimeoutExceeded,
+			wantAttempts: -1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			attempts := 0
+			released := false
+			release, err := backoffio.AcquireLock(ctx, func() (func(), error) {
+				attempts++
+				if attempts <= len(tc.errs) {
+					return nil, tc.errs[attempts-1]
+				}
+				return func() { released = true }, nil
+			}, shortInterval)
+
+			if tc.wantAttempts >= 0 {
+				assert.Equal(t, tc.wantAttempts, attempts)
+			}
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr), "got %v", err)
+				assert.Nil(t, release)
+				return
+			}
+			require.NoError(t, err)
+			release()
+			assert.True(t, released)
+		})
+	}
+}
+
+func Test_AcquireLock_ReturnsRawError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	name := filepath.Join(t.TempDir(), "lock")
+
+	_, err := backoffio.AcquireLock(ctx, func() (func(), error) {
+		_, err := os.Open(name)
+		return nil, err
+	}, shortInterval)
+
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	var retryErr *backoff.RetryError
+	assert.False(t, errors.As(err, &retryErr))
+	var pathErr *os.PathError
+	assert.True(t, errors.As(err, &pathErr))
+}
+
+func Test_RetryOpen(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	name := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(name, []byte("hello"), 0o600))
+
+	f, err := backoffio.RetryOpen(ctx, name, os.O_RDONLY, 0, shortInterval)
+	require.NoError(t, err)
+	f.Close()
+
+	_, err = backoffio.RetryOpen(ctx, name+".missing", os.O_RDONLY, 0, shortInterval)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.True(t, os.IsNotExist(err))
+	var pathErr *os.PathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, name+".missing", pathErr.Path)
+}
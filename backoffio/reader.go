@@ -0,0 +1,111 @@
+package backoffio
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/rhomel/backoff"
+)
+
+// ReaderClosed is returned by Read after Close.
+const ReaderClosed = backoff.Error("backoffio: read from closed RetryReader")
+
+// OpenFunc opens a stream starting at offset, for example with an HTTP range
+// request or an object storage read. Mark errors with backoff.Permanent to
+// stop retrying.
+type OpenFunc func(ctx context.Context, offset int64) (io.ReadCloser, error)
+
+// RetryReader presents a stream that fails midway as a single io.Reader. When
+// opening or reading the stream fails, it reopens the stream from the last
+// read offset with backoff.
+type RetryReader struct {
+	ctx   context.Context
+	open  OpenFunc
+	bo    *backoff.Backoff
+	tries int8
+
+	rc     io.ReadCloser
+	offset int64
+	err    error
+}
+
+var _ io.ReadCloser = (*RetryReader)(nil)
+
+// NewRetryReader creates a RetryReader that opens the stream from offset 0.
+// Each Read tries up to `tries` times with bo to open and read the stream
+// before it fails.
+func NewRetryReader(ctx context.Context, open OpenFunc, bo *backoff.Backoff, tries int8) *RetryReader {
+	return &RetryReader{
+		ctx:   ctx,
+		open:  open,
+		bo:    bo,
+		tries: tries,
+	}
+}
+
+// Read reads from the stream, reopening it from the last read offset after a
+// failure. Once the retries fail, Read returns the error of TryErr from then
+// on.
+func (r *RetryReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	var n int
+	var eof bool
+	err := r.bo.TryErr(r.ctx, r.tries, func(ctx context.Context) error {
+		if r.rc == nil {
+			rc, err := r.open(ctx, r.offset)
+			if err != nil {
+				return err
+			}
+			r.rc = rc
+		}
+		var err error
+		n, err = r.rc.Read(p)
+		r.offset += int64(n)
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF):
+			eof = true
+		default:
+			// reopen from the offset
+			r.rc.Close()
+			r.rc = nil
+			if n == 0 {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.err = err
+		return 0, err
+	}
+	if eof {
+		r.err = io.EOF
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Offset returns the number of bytes read.
+func (r *RetryReader) Offset() int64 {
+	return r.offset
+}
+
+// Close closes the open stream.
+func (r *RetryReader) Close() error {
+	if r.err == nil {
+		r.err = ReaderClosed
+	}
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
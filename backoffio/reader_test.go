@@ -0,0 +1,91 @@
+package backoffio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffio"
+)
+
+// flakyStream returns chunk bytes of data and then fails
+type flakyStream struct {
+	data  string
+	chunk int
+	read  int
+}
+
+func (f *flakyStream) Read(p []byte) (int, error) {
+	if f.data == "" {
+		return 0, io.EOF
+	}
+	if f.read >= f.chunk {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p[:1], f.data)
+	f.data = f.data[n:]
+	f.read += n
+	return n, nil
+}
+
+func (f *flakyStream) Close() error { return nil }
+
+func Test_RetryReader(t *testing.T) {
+	const data = "the quick brown fox jumps over the lazy dog"
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var offsets []int64
+	opens := 0
+	r := backoffio.NewRetryReader(ctx, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		opens++
+		if opens == 2 {
+			return nil, errors.New("unavailable")
+		}
+		offsets = append(offsets, offset)
+		return &flakyStream{data: data[offset:], chunk: 10}, nil
+	}, backoff.NewBackoff(shortInterval), 3)
+
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(got))
+	assert.Equal(t, []int64{0, 10, 20, 30, 40}, offsets)
+	assert.Equal(t, int64(len(data)), r.Offset())
+	require.NoError(t, r.Close())
+	_, err = r.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+}
+
+func Test_RetryReader_GivesUp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errDenied := errors.New("denied")
+	r := backoffio.NewRetryReader(ctx, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		if offset > 0 {
+			return nil, backoff.Permanent(errDenied)
+		}
+		return &flakyStream{data: "abcdef", chunk: 2}, nil
+	}, backoff.NewBackoff(shortInterval), 3)
+
+	got, err := io.ReadAll(r)
+	assert.True(t, errors.Is(err, errDenied))
+	assert.Equal(t, "ab", string(got))
+	_, err = r.Read(make([]byte, 1))
+	assert.True(t, errors.Is(err, errDenied))
+	require.NoError(t, r.Close())
+
+	r = backoffio.NewRetryReader(ctx, func(ctx context.Context, offset int64) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader("x")), nil
+	}, backoff.NewBackoff(shortInterval), 3)
+	require.NoError(t, r.Close())
+	_, err = r.Read(make([]byte, 1))
+	assert.Equal(t, backoffio.ReaderClosed, err)
+}
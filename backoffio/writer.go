@@ -0,0 +1,149 @@
+package backoffio
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+const (
+	// WriterClosed is returned by Write after Close
+	WriterClosed = backoff.Error("backoffio: write to closed RetryWriter")
+	// NoProgress is the error of an upload that returned without error but
+	// acknowledged no bytes
+	NoProgress = backoff.Error("backoffio: upload acknowledged no bytes")
+)
+
+// UploadFunc uploads chunk starting at offset, for example as a part of a
+// multipart upload to object storage. It returns the acknowledged offset, the
+// number of bytes of the whole upload that are persisted, which may be short
+// of offset+len(chunk) when it fails midway. Mark errors with
+// backoff.Permanent to stop retrying.
+type UploadFunc func(ctx context.Context, offset int64, chunk []byte) (acked int64, err error)
+
+// WriterConfig configures a RetryWriter.
+type WriterConfig struct {
+	// Upload uploads the chunks
+	Upload UploadFunc
+	// Backoff retries a failed chunk
+	Backoff *backoff.Backoff
+	// Tries is the number of tries of each chunk
+	Tries int8
+	// ChunkSize is the size of the chunks passed to Upload, except the last
+	// one which may be smaller. Defaults to 5 MiB.
+	ChunkSize int
+	// Offset is the acknowledged offset to resume an upload from, for example
+	// after a restart. The first byte written is uploaded at Offset.
+	Offset int64
+	// Budget limits the time of the whole upload. Zero does not limit it.
+	Budget time.Duration
+}
+
+// defaultChunkSize is the minimum part size of common object storage
+const defaultChunkSize = 5 << 20
+
+// RetryWriter uploads what is written to it in chunks. A failed chunk is
+// retried with backoff from the last acknowledged offset, so a chunk that
+// failed midway is not uploaded again from its start. Close uploads the last
+// chunk.
+type RetryWriter struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	cfg    WriterConfig
+
+	buf   []byte
+	acked int64
+	err   error
+}
+
+var _ io.WriteCloser = (*RetryWriter)(nil)
+
+// NewRetryWriter creates a new RetryWriter. The Budget starts counting now.
+func NewRetryWriter(ctx context.Context, cfg WriterConfig) *RetryWriter {
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	cancel := context.CancelFunc(func() {})
+	if cfg.Budget > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Budget)
+	}
+	return &RetryWriter{
+		ctx:    ctx,
+		cancel: cancel,
+		cfg:    cfg,
+		acked:  cfg.Offset,
+	}
+}
+
+// Write buffers p and uploads the full chunks. Once a chunk fails, Write
+// returns the error of TryErr from then on.
+func (w *RetryWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= w.cfg.ChunkSize {
+		if err := w.flush(w.cfg.ChunkSize); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Close uploads the last chunk and returns its error, or the error of an
+// earlier chunk.
+func (w *RetryWriter) Close() error {
+	defer w.cancel()
+	if w.err != nil {
+		if w.err == WriterClosed {
+			return nil
+		}
+		return w.err
+	}
+	if len(w.buf) > 0 {
+		if err := w.flush(len(w.buf)); err != nil {
+			return err
+		}
+	}
+	w.err = WriterClosed
+	return nil
+}
+
+// Acked returns the acknowledged offset, to resume the upload from with
+// WriterConfig.Offset.
+func (w *RetryWriter) Acked() int64 {
+	return w.acked
+}
+
+// flush uploads the first n buffered bytes
+func (w *RetryWriter) flush(n int) error {
+	start := w.acked
+	end := start + int64(n)
+	err := w.cfg.Backoff.TryErr(w.ctx, w.cfg.Tries, func(ctx context.Context) error {
+		for w.acked < end {
+			acked, err := w.cfg.Upload(ctx, w.acked, w.buf[w.acked-start:n])
+			if acked > end {
+				acked = end
+			}
+			progress := acked > w.acked
+			if progress {
+				w.acked = acked
+			}
+			if err != nil {
+				return err
+			}
+			if !progress {
+				return NoProgress
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		w.err = err
+		return err
+	}
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	return nil
+}
@@ -0,0 +1,97 @@
+package backoffio_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffio"
+)
+
+// flakyUpload persists at most limit bytes per call and fails every other call
+type flakyUpload struct {
+	data  bytes.Buffer
+	limit int
+	calls int
+}
+
+func (f *flakyUpload) upload(ctx context.Context, offset int64, chunk []byte) (int64, error) {
+	f.calls++
+	if offset != int64(f.data.Len()) {
+		return int64(f.data.Len()), backoff.Permanent(errors.New("offset gap"))
+	}
+	if len(chunk) > f.limit {
+		chunk = chunk[:f.limit]
+	}
+	f.data.Write(chunk)
+	if f.calls%2 == 0 {
+		return int64(f.data.Len()), errors.New("connection reset")
+	}
+	return int64(f.data.Len()), nil
+}
+
+func Test_RetryWriter(t *testing.T) {
+	const data = "the quick brown fox jumps over the lazy dog"
+	up := &flakyUpload{limit: 3}
+	w := backoffio.NewRetryWriter(context.Background(), backoffio.WriterConfig{
+		Upload:    up.upload,
+		Backoff:   backoff.NewBackoff(shortInterval),
+		Tries:     5,
+		ChunkSize: 8,
+		Budget:    time.Second,
+	})
+	for i := 0; i < len(data); i += 5 {
+		end := i + 5
+		if end > len(data) {
+			end = len(data)
+		}
+		n, err := w.Write([]byte(data[i:end]))
+		require.NoError(t, err)
+		assert.Equal(t, end-i, n)
+	}
+	assert.Equal(t, int64(40), w.Acked())
+	require.NoError(t, w.Close())
+	assert.Equal(t, data, up.data.String())
+	assert.Equal(t, int64(len(data)), w.Acked())
+
+	_, err := w.Write([]byte("x"))
+	assert.Equal(t, backoffio.WriterClosed, err)
+	assert.NoError(t, w.Close())
+}
+
+func Test_RetryWriter_Resume(t *testing.T) {
+	up := &flakyUpload{limit: 100}
+	up.data.WriteString("hello ")
+	w := backoffio.NewRetryWriter(context.Background(), backoffio.WriterConfig{
+		Upload:  up.upload,
+		Backoff: backoff.NewBackoff(shortInterval),
+		Tries:   3,
+		Offset:  6,
+	})
+	_, err := w.Write([]byte("world"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "hello world", up.data.String())
+}
+
+func Test_RetryWriter_Budget(t *testing.T) {
+	w := backoffio.NewRetryWriter(context.Background(), backoffio.WriterConfig{
+		Upload: func(ctx context.Context, offset int64, chunk []byte) (int64, error) {
+			return offset, nil
+		},
+		Backoff:   backoff.NewBackoff(shortInterval),
+		Tries:     backoff.InfiniteTries,
+		ChunkSize: 1,
+		Budget:    20 * time.Millisecond,
+	})
+	_, err := w.Write([]byte("x"))
+	assert.True(t, errors.Is(err, backoff.BackoffContextTimeoutExceeded), "got %v", err)
+	assert.True(t, errors.Is(err, backoffio.NoProgress), "got %v", err)
+	assert.Equal(t, err, w.Close())
+}
@@ -0,0 +1,122 @@
+// Package backoffsdk adapts backoff Intervals to the retry interfaces of cloud
+// SDKs so all clients can share the same schedules.
+//
+// The adapters satisfy the SDK interfaces structurally and do not import the
+// SDKs, so using this package does not add dependencies.
+package backoffsdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// AWSRetryer implements the aws.Retryer and aws.RetryerV2 interfaces of
+// github.com/aws/aws-sdk-go-v2. It is stateless so it can be shared by
+// concurrent operations:
+//
+//	cfg.Retryer = func() aws.Retryer {
+//		return backoffsdk.NewAWSRetryer(backoff.DefaultBinaryExponential(), 5, nil)
+//	}
+type AWSRetryer struct {
+	intervals   backoff.Intervals
+	maxAttempts int
+	retryable   func(error) bool
+}
+
+// NewAWSRetryer creates a new AWSRetryer. retryable decides which errors are
+// retried; nil retries every error.
+func NewAWSRetryer(iv backoff.Intervals, maxAttempts int, retryable func(error) bool) *AWSRetryer {
+	return &AWSRetryer{
+		intervals:   iv,
+		maxAttempts: maxAttempts,
+		retryable:   retryable,
+	}
+}
+
+// IsErrorRetryable reports if err should be retried.
+func (r *AWSRetryer) IsErrorRetryable(err error) bool {
+	if r.retryable == nil {
+		return err != nil
+	}
+	return r.retryable(err)
+}
+
+// MaxAttempts returns the maximum number of attempts including the first.
+func (r *AWSRetryer) MaxAttempts() int {
+	return r.maxAttempts
+}
+
+// RetryDelay returns the pause before retrying after `attempt` failed. The SDK
+// counts attempts from 1.
+func (r *AWSRetryer) RetryDelay(attempt int, opErr error) (time.Duration, error) {
+	if attempt < 1 {
+		attempt = 1
+	}
+	schedule := backoff.Schedule(r.intervals, attempt)
+	return schedule[len(schedule)-1].Interval, nil
+}
+
+// GetRetryToken returns a no-op token. Retry quotas are not supported.
+func (r *AWSRetryer) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	return noopRelease, nil
+}
+
+// GetInitialToken returns a no-op token.
+func (r *AWSRetryer) GetInitialToken() func(error) error {
+	return noopRelease
+}
+
+// GetAttemptToken returns a no-op token.
+func (r *AWSRetryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	return noopRelease, nil
+}
+
+func noopRelease(error) error {
+	return nil
+}
+
+// GaxRetryer implements the gax.Retryer interface of
+// github.com/googleapis/gax-go/v2 used by the Google Cloud client libraries. A
+// GaxRetryer keeps the state of a single call so create a new one per call:
+//
+//	gax.WithRetry(func() gax.Retryer {
+//		return backoffsdk.NewGaxRetryer(backoff.DefaultBinaryExponential(), 5, nil)
+//	})
+type GaxRetryer struct {
+	intervals   backoff.Intervals
+	maxAttempts int
+	retryable   func(error) bool
+
+	i        int8 // index into the Intervals series
+	attempts int  // failed attempts so far
+	last     time.Duration
+}
+
+// NewGaxRetryer creates a new GaxRetryer. retryable decides which errors are
+// retried; nil retries every error.
+func NewGaxRetryer(iv backoff.Intervals, maxAttempts int, retryable func(error) bool) *GaxRetryer {
+	return &GaxRetryer{
+		intervals:   iv,
+		maxAttempts: maxAttempts,
+		retryable:   retryable,
+	}
+}
+
+// Retry returns the pause before the next attempt and if err should be
+// retried at all.
+func (r *GaxRetryer) Retry(err error) (time.Duration, bool) {
+	if r.attempts+1 >= r.maxAttempts {
+		return 0, false
+	}
+	if r.retryable != nil && !r.retryable(err) {
+		return 0, false
+	}
+	r.last = r.intervals.Next(r.i, r.last)
+	r.attempts++
+	if r.i < backoff.InfiniteTries {
+		r.i++
+	}
+	return r.last, true
+}
@@ -0,0 +1,100 @@
+package backoffsdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffsdk"
+)
+
+// copies of the SDK interfaces to check the adapters satisfy them
+
+type awsRetryer interface {
+	IsErrorRetryable(error) bool
+	MaxAttempts() int
+	RetryDelay(attempt int, opErr error) (time.Duration, error)
+	GetRetryToken(ctx context.Context, opErr error) (releaseToken func(error) error, err error)
+	GetInitialToken() (releaseToken func(error) error)
+}
+
+type awsRetryerV2 interface {
+	awsRetryer
+	GetAttemptToken(context.Context) (func(error) error, error)
+}
+
+type gaxRetryer interface {
+	Retry(err error) (pause time.Duration, shouldRetry bool)
+}
+
+var (
+	_ awsRetryerV2 = (*backoffsdk.AWSRetryer)(nil)
+	_ gaxRetryer   = (*backoffsdk.GaxRetryer)(nil)
+)
+
+func Test_AWSRetryer(t *testing.T) {
+	notRetryable := errors.New("validation")
+	r := backoffsdk.NewAWSRetryer(backoff.DefaultBinaryExponential(), 4, func(err error) bool {
+		return !errors.Is(err, notRetryable)
+	})
+
+	assert.Equal(t, 4, r.MaxAttempts())
+	assert.True(t, r.IsErrorRetryable(errors.New("throttled")))
+	assert.False(t, r.IsErrorRetryable(notRetryable))
+
+	var got []time.Duration
+	for attempt := 1; attempt <= 3; attempt++ {
+		d, err := r.RetryDelay(attempt, nil)
+		assert.NoError(t, err)
+		got = append(got, d)
+	}
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second}, got)
+
+	release, err := r.GetRetryToken(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.NoError(t, release(nil))
+	assert.NoError(t, r.GetInitialToken()(nil))
+}
+
+func Test_GaxRetryer(t *testing.T) {
+	r := backoffsdk.NewGaxRetryer(backoff.DefaultBinaryExponential(), 3, nil)
+
+	pause, ok := r.Retry(errors.New("unavailable"))
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, pause)
+
+	pause, ok = r.Retry(errors.New("unavailable"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, pause)
+
+	// the third attempt was the last
+	_, ok = r.Retry(errors.New("unavailable"))
+	assert.False(t, ok)
+}
+
+func Test_GaxRetryer_NotRetryable(t *testing.T) {
+	r := backoffsdk.NewGaxRetryer(backoff.DefaultBinaryExponential(), 3, func(err error) bool {
+		return false
+	})
+	_, ok := r.Retry(errors.New("not found"))
+	assert.False(t, ok)
+}
+
+func Test_GaxRetryer_ManyAttempts(t *testing.T) {
+	r := backoffsdk.NewGaxRetryer(backoff.DefaultBinaryExponential(), 200, nil)
+
+	retries := 0
+	for {
+		if _, ok := r.Retry(errors.New("unavailable")); !ok {
+			break
+		}
+		retries++
+		require.LessOrEqual(t, retries, 200)
+	}
+	assert.Equal(t, 199, retries)
+}
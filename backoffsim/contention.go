@@ -0,0 +1,135 @@
+package backoffsim
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// ContentionConfig describes a simulation of many clients retrying against a
+// server with limited capacity.
+type ContentionConfig struct {
+	// Intervals is the policy of every client
+	Intervals backoff.Intervals
+	// Tries is the number of tries of each client
+	Tries int8
+	// Clients is the number of clients
+	Clients int
+	// Capacity is the number of attempts the server accepts per Tick. The
+	// attempts beyond it fail.
+	Capacity int
+	// Tick is the width of the time buckets. Defaults to 100ms.
+	Tick time.Duration
+	// StartSpread spreads the first attempts of the clients uniformly over
+	// [0, StartSpread). Zero starts all clients at the same time, like after
+	// a deploy or an outage.
+	StartSpread time.Duration
+	// Model optionally fails attempts the server accepted, for example an
+	// Outage. `at` is measured from the start of the simulation.
+	Model FailureModel
+	// AttemptDuration is how long each attempt takes
+	AttemptDuration time.Duration
+	// Seed seeds the random numbers of the simulation
+	Seed int64
+}
+
+// ContentionResult is the outcome of a contention simulation.
+type ContentionResult struct {
+	// Tick is the width of the time buckets
+	Tick time.Duration
+	// Load is the number of attempts offered to the server per Tick
+	Load []int
+	// Rejected is the number of attempts per Tick beyond the capacity
+	Rejected []int
+	// Clients is the number of simulated clients
+	Clients int
+	// Successes is the number of clients that succeeded
+	Successes int
+}
+
+// PeakLoad returns the highest number of attempts offered in a Tick.
+func (r ContentionResult) PeakLoad() int {
+	peak := 0
+	for _, l := range r.Load {
+		if l > peak {
+			peak = l
+		}
+	}
+	return peak
+}
+
+// client is the retry state of a simulated client
+type client struct {
+	next     time.Duration
+	attempts int
+	i        int8
+	wait     time.Duration
+}
+
+// clients orders the clients by their next attempt
+type clients []*client
+
+func (c clients) Len() int            { return len(c) }
+func (c clients) Less(i, j int) bool  { return c[i].next < c[j].next }
+func (c clients) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *clients) Push(x interface{}) { *c = append(*c, x.(*client)) }
+func (c *clients) Pop() interface{} {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
+
+// SimulateContention runs the simulation described by cfg. The attempts of the
+// clients are processed in time order with a virtual clock.
+func SimulateContention(cfg ContentionConfig) ContentionResult {
+	tick := cfg.Tick
+	if tick <= 0 {
+		tick = 100 * time.Millisecond
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	result := ContentionResult{Tick: tick, Clients: cfg.Clients}
+
+	queue := make(clients, 0, cfg.Clients)
+	for n := 0; n < cfg.Clients; n++ {
+		c := &client{}
+		if cfg.StartSpread > 0 {
+			c.next = time.Duration(rng.Int63n(int64(cfg.StartSpread)))
+		}
+		queue = append(queue, c)
+	}
+	heap.Init(&queue)
+
+	for queue.Len() > 0 {
+		c := heap.Pop(&queue).(*client)
+		bucket := int(c.next / tick)
+		for len(result.Load) <= bucket {
+			result.Load = append(result.Load, 0)
+			result.Rejected = append(result.Rejected, 0)
+		}
+		result.Load[bucket]++
+		c.attempts++
+		fails := result.Load[bucket] > cfg.Capacity
+		if fails {
+			result.Rejected[bucket]++
+		} else {
+			fails = cfg.Model != nil && cfg.Model(rng, c.attempts, c.next)
+		}
+		if !fails {
+			result.Successes++
+			continue
+		}
+		if cfg.Tries != backoff.InfiniteTries && c.attempts >= int(cfg.Tries) {
+			continue
+		}
+		c.wait = cfg.Intervals.Next(c.i, c.wait)
+		if c.i < backoff.InfiniteTries {
+			c.i++
+		}
+		c.next += cfg.AttemptDuration + c.wait
+		heap.Push(&queue, c)
+	}
+	return result
+}
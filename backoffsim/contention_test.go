@@ -0,0 +1,83 @@
+package backoffsim_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffsim"
+)
+
+func Test_SimulateContention(t *testing.T) {
+	constant := backoff.Exponential{
+		Base:    time.Second,
+		Unit:    time.Second,
+		Initial: 500 * time.Millisecond,
+		Max:     500 * time.Millisecond,
+	}
+
+	result := backoffsim.SimulateContention(backoffsim.ContentionConfig{
+		Intervals: constant,
+		Tries:     backoff.InfiniteTries,
+		Clients:   100,
+		Capacity:  10,
+	})
+
+	// without jitter the rejected clients retry in synchronized waves
+	assert.Equal(t, 100*time.Millisecond, result.Tick)
+	assert.Equal(t, 100, result.Successes)
+	assert.Equal(t, 100, result.PeakLoad())
+	for wave := 0; wave < 10; wave++ {
+		assert.Equal(t, 100-10*wave, result.Load[wave*5], "wave %d", wave)
+		assert.Equal(t, 90-10*wave, result.Rejected[wave*5], "wave %d", wave)
+	}
+}
+
+func Test_SimulateContention_Jitter(t *testing.T) {
+	constant := backoff.Exponential{
+		Base:    time.Second,
+		Unit:    time.Second,
+		Initial: 500 * time.Millisecond,
+		Max:     500 * time.Millisecond,
+	}
+	jitter := backoff.ExponentialJitter{
+		Exponential: constant,
+		JitterMax:   400 * time.Millisecond,
+		Rand:        rand.New(rand.NewSource(1)),
+	}
+
+	cases := map[string]struct {
+		iv backoff.Intervals
+	}{
+		"Constant": {iv: constant},
+		"Jitter":   {iv: jitter},
+	}
+
+	peaks := make(map[string]int)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			result := backoffsim.SimulateContention(backoffsim.ContentionConfig{
+				Intervals: tc.iv,
+				Tries:     backoff.InfiniteTries,
+				Clients:   100,
+				Capacity:  10,
+				// all attempts fail during the first second
+				Model: backoffsim.Outage(0, time.Second),
+			})
+			assert.Equal(t, 100, result.Successes)
+			// the peak after the initial burst
+			peak := 0
+			for _, l := range result.Load[1:] {
+				if l > peak {
+					peak = l
+				}
+			}
+			peaks[name] = peak
+		})
+	}
+	assert.Less(t, peaks["Jitter"], peaks["Constant"])
+}
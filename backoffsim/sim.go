@@ -0,0 +1,154 @@
+// Package backoffsim simulates retry policies against synthetic failure models
+// so policies can be evaluated before they are deployed. The simulation uses a
+// virtual clock, so it runs much faster than the simulated time.
+package backoffsim
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// FailureModel decides if an attempt fails. attempt is the attempt number of
+// the run starting from 1 and at is when the attempt starts, measured from the
+// start of the run.
+type FailureModel func(r *rand.Rand, attempt int, at time.Duration) bool
+
+// FailFirst fails the first n attempts of every run.
+func FailFirst(n int) FailureModel {
+	return func(r *rand.Rand, attempt int, at time.Duration) bool {
+		return attempt <= n
+	}
+}
+
+// FailWithProbability fails every attempt independently with probability p.
+func FailWithProbability(p float64) FailureModel {
+	return func(r *rand.Rand, attempt int, at time.Duration) bool {
+		return r.Float64() < p
+	}
+}
+
+// Outage fails the attempts that start in the window [start, end).
+func Outage(start, end time.Duration) FailureModel {
+	return func(r *rand.Rand, attempt int, at time.Duration) bool {
+		return at >= start && at < end
+	}
+}
+
+// Config describes a simulation.
+type Config struct {
+	// Intervals is the policy to simulate
+	Intervals backoff.Intervals
+	// Tries is the number of tries of each run
+	Tries int8
+	// Model decides which attempts fail
+	Model FailureModel
+	// AttemptDuration is how long each attempt takes
+	AttemptDuration time.Duration
+	// Runs is the number of simulated Try calls. Defaults to 1000.
+	Runs int
+	// Seed seeds the random numbers of the failure model
+	Seed int64
+}
+
+// Distribution summarizes a set of durations.
+type Distribution struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Runs is the number of simulated Try calls
+	Runs int
+	// Successes is the number of runs that succeeded
+	Successes int
+	// Latency is the distribution of the total time of the runs, including
+	// the runs that gave up
+	Latency Distribution
+	// Attempts counts the runs by the attempts they used: Attempts[n] is the
+	// number of runs that made n attempts
+	Attempts map[int]int
+}
+
+// SuccessProbability is the fraction of runs that succeeded.
+func (r Result) SuccessProbability() float64 {
+	if r.Runs == 0 {
+		return 0
+	}
+	return float64(r.Successes) / float64(r.Runs)
+}
+
+// Simulate runs the simulation described by cfg.
+func Simulate(cfg Config) Result {
+	runs := cfg.Runs
+	if runs <= 0 {
+		runs = 1000
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	result := Result{
+		Runs:     runs,
+		Attempts: make(map[int]int),
+	}
+	latencies := make([]time.Duration, 0, runs)
+	for n := 0; n < runs; n++ {
+		took, attempts, ok := simulateRun(cfg, rng)
+		latencies = append(latencies, took)
+		result.Attempts[attempts]++
+		if ok {
+			result.Successes++
+		}
+	}
+	result.Latency = distribution(latencies)
+	return result
+}
+
+// simulateRun simulates a single Try call with a virtual clock
+func simulateRun(cfg Config, rng *rand.Rand) (took time.Duration, attempts int, ok bool) {
+	var i int8
+	var wait time.Duration
+	for {
+		attempts++
+		fails := cfg.Model != nil && cfg.Model(rng, attempts, took)
+		took += cfg.AttemptDuration
+		if !fails {
+			return took, attempts, true
+		}
+		if cfg.Tries != backoff.InfiniteTries && attempts >= int(cfg.Tries) {
+			return took, attempts, false
+		}
+		wait = cfg.Intervals.Next(i, wait)
+		took += wait
+		if i < backoff.InfiniteTries {
+			i++
+		}
+	}
+}
+
+func distribution(ds []time.Duration) Distribution {
+	if len(ds) == 0 {
+		return Distribution{}
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	percentile := func(p float64) time.Duration {
+		return ds[int(p*float64(len(ds)-1))]
+	}
+	return Distribution{
+		Min:  ds[0],
+		Mean: sum / time.Duration(len(ds)),
+		P50:  percentile(0.5),
+		P90:  percentile(0.9),
+		P99:  percentile(0.99),
+		Max:  ds[len(ds)-1],
+	}
+}
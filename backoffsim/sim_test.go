@@ -0,0 +1,80 @@
+package backoffsim_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffsim"
+)
+
+func Test_Simulate(t *testing.T) {
+	cases := map[string]struct {
+		model          backoffsim.FailureModel
+		wantSuccess    float64
+		wantAttempts   map[int]int
+		wantMaxLatency time.Duration
+	}{
+		"Never fails": {
+			wantSuccess:    1,
+			wantAttempts:   map[int]int{1: 100},
+			wantMaxLatency: 100 * time.Millisecond,
+		},
+		"Fail first 2": {
+			model:        backoffsim.FailFirst(2),
+			wantSuccess:  1,
+			wantAttempts: map[int]int{3: 100},
+			// 3 attempts, 0.5s and 1s pauses
+			wantMaxLatency: 1800 * time.Millisecond,
+		},
+		"Fail first 5": {
+			model:          backoffsim.FailFirst(5),
+			wantSuccess:    0,
+			wantAttempts:   map[int]int{4: 100},
+			wantMaxLatency: 3900 * time.Millisecond,
+		},
+		"Outage during the first 2 seconds": {
+			model:        backoffsim.Outage(0, 2*time.Second),
+			wantSuccess:  1,
+			wantAttempts: map[int]int{4: 100},
+			// attempts at 0, 0.6s, 1.7s and 3.8s
+			wantMaxLatency: 3900 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			result := backoffsim.Simulate(backoffsim.Config{
+				Intervals:       backoff.DefaultBinaryExponential(),
+				Tries:           4,
+				Model:           tc.model,
+				AttemptDuration: 100 * time.Millisecond,
+				Runs:            100,
+			})
+			assert.Equal(t, 100, result.Runs)
+			assert.Equal(t, tc.wantSuccess, result.SuccessProbability())
+			assert.Equal(t, tc.wantAttempts, result.Attempts)
+			assert.Equal(t, tc.wantMaxLatency, result.Latency.Max)
+			assert.Equal(t, tc.wantMaxLatency, result.Latency.P50)
+		})
+	}
+}
+
+func Test_Simulate_Probability(t *testing.T) {
+	result := backoffsim.Simulate(backoffsim.Config{
+		Intervals: backoff.DefaultBinaryExponential(),
+		Tries:     3,
+		Model:     backoffsim.FailWithProbability(0.5),
+		Runs:      10000,
+		Seed:      1,
+	})
+	// 1 - 0.5^3
+	assert.InDelta(t, 0.875, result.SuccessProbability(), 0.02)
+	assert.Equal(t, time.Duration(0), result.Latency.Min)
+	assert.Equal(t, 1500*time.Millisecond, result.Latency.Max)
+	assert.True(t, result.Latency.P50 <= result.Latency.P90)
+	assert.True(t, result.Latency.P90 <= result.Latency.P99)
+}
@@ -0,0 +1,86 @@
+package backoffstate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RedisClient is the subset of a Redis client used by RedisStore. Get returns
+// ok false for a missing key. It does not import a Redis library, so adapt the
+// client of your choice, for example github.com/redis/go-redis:
+//
+//	type goRedis struct{ c *redis.Client }
+//
+//	func (r goRedis) Get(ctx context.Context, key string) (string, bool, error) {
+//		v, err := r.c.Get(ctx, key).Result()
+//		if err == redis.Nil {
+//			return "", false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//
+//	func (r goRedis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+//		return r.c.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (r goRedis) Del(ctx context.Context, key string) error {
+//		return r.c.Del(ctx, key).Err()
+//	}
+type RedisClient interface {
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisStore is a Store for workers in different processes. The State is
+// stored as a string under the key with a prefix. Get and Set are not atomic
+// together, so concurrent failures of the same key may advance the series
+// once instead of twice, which is fine for backing off.
+type RedisStore struct {
+	client RedisClient
+	prefix string
+	idle   time.Duration
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a new RedisStore. Keys expire `idle` after their next
+// allowed attempt so the State of resources that recovered is cleaned up.
+func NewRedisStore(client RedisClient, prefix string, idle time.Duration) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: prefix,
+		idle:   idle,
+	}
+}
+
+// Get returns the State of key.
+func (r *RedisStore) Get(ctx context.Context, key string) (State, error) {
+	v, ok, err := r.client.Get(ctx, r.prefix+key)
+	if err != nil || !ok {
+		return State{}, err
+	}
+	var (
+		s           State
+		last, after int64
+	)
+	// values stored before State.Attempts have three fields
+	n, err := fmt.Sscanf(v, "%d %d %d %d", &s.Index, &last, &after, &s.Attempts)
+	if err != nil && (n != 3 || len(strings.Fields(v)) != 3) {
+		return State{}, fmt.Errorf("backoffstate: invalid state %q: %w", v, err)
+	}
+	s.Last = time.Duration(last)
+	s.NextAllowed = time.Unix(0, after)
+	return s, nil
+}
+
+// Set stores the State of key. The zero State removes the key.
+func (r *RedisStore) Set(ctx context.Context, key string, s State) error {
+	if s == (State{}) {
+		return r.client.Del(ctx, r.prefix+key)
+	}
+	v := fmt.Sprintf("%d %d %d %d", s.Index, int64(s.Last), s.NextAllowed.UnixNano(), s.Attempts)
+	return r.client.Set(ctx, r.prefix+key, v, time.Until(s.NextAllowed)+r.idle)
+}
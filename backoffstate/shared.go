@@ -0,0 +1,81 @@
+package backoffstate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// Shared runs attempts against keys following a backoff schedule kept in a
+// Store, so every worker sharing the Store waits for the same next allowed
+// time after a failure of any of them.
+type Shared struct {
+	store     Store
+	intervals backoff.Intervals
+	now       func() time.Time
+}
+
+// NewShared creates a new Shared that computes pauses with iv.
+func NewShared(store Store, iv backoff.Intervals) *Shared {
+	return &Shared{
+		store:     store,
+		intervals: iv,
+		now:       time.Now,
+	}
+}
+
+// Do waits until an attempt for key is allowed and calls fn once. A failure
+// advances the shared schedule of key and a success resets it. Do returns the
+// error of fn, or the Store error, or the context error if ctx is done while
+// waiting.
+func (s *Shared) Do(ctx context.Context, key string, fn backoff.Operation) error {
+	state, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if wait := state.NextAllowed.Sub(s.now()); wait > 0 {
+		if err := backoff.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	if err := fn(ctx); err != nil {
+		// re-read the state in case another worker failed meanwhile
+		if latest, getErr := s.store.Get(ctx, key); getErr == nil {
+			state = latest
+		}
+		pause := s.intervals.Next(state.Index, state.Last)
+		next := State{Index: state.Index, Last: pause, NextAllowed: s.now().Add(pause), Attempts: state.Attempts + 1}
+		if next.Index < backoff.InfiniteTries {
+			next.Index++
+		}
+		if setErr := s.store.Set(ctx, key, next); setErr != nil {
+			return fmt.Errorf("%w (store: %v)", err, setErr)
+		}
+		return err
+	}
+	return s.store.Set(ctx, key, State{})
+}
+
+// Try calls Do until it succeeds or `tries` attempts failed, in which case it
+// returns the last error. Like Backoff.Try, zero tries make a single attempt
+// and negative tries return backoff.NegativeTries.
+func (s *Shared) Try(ctx context.Context, key string, tries int8, fn backoff.Operation) error {
+	switch {
+	case tries < 0:
+		return backoff.NegativeTries
+	case tries == 0:
+		tries = 1
+	}
+	var err error
+	for i := int8(0); i < tries || tries == backoff.InfiniteTries; i++ {
+		if err = s.Do(ctx, key, fn); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,214 @@
+package backoffstate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffstate"
+)
+
+var shortInterval = backoff.Exponential{
+	Base:    2 * time.Millisecond,
+	Unit:    time.Millisecond,
+	Initial: 10 * time.Millisecond,
+	Max:     100 * time.Millisecond,
+}
+
+// fakeRedis is an in-memory RedisClient
+type fakeRedis struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	v, ok := f.values[key]
+	return v, ok, nil
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	f.values[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func (f *fakeRedis) Del(ctx context.Context, key string) error {
+	delete(f.values, key)
+	delete(f.ttls, key)
+	return nil
+}
+
+func Test_Shared(t *testing.T) {
+	cases := map[string]struct {
+		store backoffstate.Store
+	}{
+		"MemoryStore": {
+			store: backoffstate.NewMemoryStore(),
+		},
+		"RedisStore": {
+			store: backoffstate.NewRedisStore(newFakeRedis(), "backoff:", time.Minute),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			workerA := backoffstate.NewShared(tc.store, shortInterval)
+			workerB := backoffstate.NewShared(tc.store, shortInterval)
+
+			// a failure of worker A delays worker B
+			start := time.Now()
+			assert.Error(t, workerA.Do(ctx, "db", func(ctx context.Context) error { return assert.AnError }))
+			state, err := tc.store.Get(ctx, "db")
+			require.NoError(t, err)
+			assert.Equal(t, int8(1), state.Index)
+			assert.Equal(t, 10*time.Millisecond, state.Last)
+			assert.Equal(t, 1, state.Attempts)
+
+			assert.Error(t, workerB.Do(ctx, "db", func(ctx context.Context) error { return assert.AnError }))
+			assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+			state, err = tc.store.Get(ctx, "db")
+			require.NoError(t, err)
+			assert.Equal(t, int8(2), state.Index)
+			assert.Equal(t, 20*time.Millisecond, state.Last)
+			assert.Equal(t, 2, state.Attempts)
+
+			// a success resets the shared state
+			calls := 0
+			err = workerA.Try(ctx, "db", 3, func(ctx context.Context) error {
+				calls++
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, calls)
+			state, err = tc.store.Get(ctx, "db")
+			require.NoError(t, err)
+			assert.Equal(t, backoffstate.State{}, state)
+		})
+	}
+}
+
+func Test_Shared_TryGivesUp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	shared := backoffstate.NewShared(backoffstate.NewMemoryStore(), shortInterval)
+
+	calls := 0
+	err := shared.Try(ctx, "db", 3, func(ctx context.Context) error {
+		calls++
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 3, calls)
+}
+
+func Test_Shared_TryTries(t *testing.T) {
+	cases := map[string]struct {
+		tries     int8
+		wantErr   error
+		wantCalls int
+	}{
+		"Zero tries make a single attempt": {
+			tries:     0,
+			wantErr:   assert.AnError,
+			wantCalls: 1,
+		},
+		"Negative tries": {
+			tries:     -2,
+			wantErr:   backoff.NegativeTries,
+			wantCalls: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			shared := backoffstate.NewShared(backoffstate.NewMemoryStore(), shortInterval)
+
+			calls := 0
+			err := shared.Try(ctx, "db", tc.tries, func(ctx context.Context) error {
+				calls++
+				return assert.AnError
+			})
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func Test_RedisStore_InvalidState(t *testing.T) {
+	client := newFakeRedis()
+	client.values["backoff:db"] = "garbage"
+	store := backoffstate.NewRedisStore(client, "backoff:", time.Minute)
+
+	_, err := store.Get(context.Background(), "db")
+	assert.Error(t, err)
+}
+
+func Test_RedisStore_StateWithoutAttempts(t *testing.T) {
+	client := newFakeRedis()
+	client.values["backoff:db"] = "2 20000000 1000"
+	store := backoffstate.NewRedisStore(client, "backoff:", time.Minute)
+
+	state, err := store.Get(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, backoffstate.State{Index: 2, Last: 20 * time.Millisecond, NextAllowed: time.Unix(0, 1000)}, state)
+}
+
+func Test_RedisStore_MonotonicAttemptsAcrossProcesses(t *testing.T) {
+	client := newFakeRedis()
+	var numbers []int
+
+	// each process resumes from the stored state and stops after two attempts
+	process := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		store := backoffstate.NewRedisStore(client, "backoff:", time.Minute)
+		state, err := store.Get(ctx, "db")
+		require.NoError(t, err)
+
+		bo := backoff.NewBackoff(shortInterval,
+			backoff.WithAttemptNumbering(backoff.AttemptsMonotonic),
+			backoff.WithAttemptsMade(state.Attempts))
+		attempts := 0
+		err = bo.TryErrFrom(ctx, 10, func(ctx context.Context) error {
+			a, _ := backoff.ScheduleFromContext(ctx)
+			numbers = append(numbers, a.Number)
+			if attempts++; attempts == 2 {
+				cancel()
+			}
+			return assert.AnError
+		}, state.Index, state.Last)
+
+		var retryErr *backoff.RetryError
+		require.ErrorAs(t, err, &retryErr)
+		require.NoError(t, store.Set(context.Background(), "db", backoffstate.State{
+			Index:       state.Index + int8(retryErr.Attempts),
+			Last:        shortInterval.Initial,
+			NextAllowed: time.Now(),
+			Attempts:    state.Attempts + retryErr.Attempts,
+		}))
+	}
+	process()
+	process()
+
+	assert.Equal(t, []int{1, 2, 3, 4}, numbers)
+	state, err := backoffstate.NewRedisStore(client, "backoff:", time.Minute).Get(context.Background(), "db")
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.Attempts)
+	assert.Equal(t, int8(4), state.Index)
+}
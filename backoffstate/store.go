@@ -0,0 +1,63 @@
+// Package backoffstate shares backoff state between horizontally scaled
+// workers, so they back off together from the same external resource instead
+// of each keeping its own schedule.
+package backoffstate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// State is the backoff state of a key.
+type State struct {
+	// Index is the iteration `i` passed to Intervals.Next for the next failure
+	Index int8
+	// Last is the last backoff pause, the `last` passed to Intervals.Next
+	Last time.Duration
+	// NextAllowed is when the next attempt may be made
+	NextAllowed time.Time
+	// Attempts is the number of failed attempts since the last success. Pass
+	// it to backoff.WithAttemptsMade so the attempt numbers of
+	// backoff.AttemptsMonotonic continue in another process.
+	Attempts int
+}
+
+// Store keeps the State per key. Get returns the zero State for an unknown
+// key. Implementations must be safe for concurrent use.
+type Store interface {
+	Get(ctx context.Context, key string) (State, error)
+	Set(ctx context.Context, key string, s State) error
+}
+
+// MemoryStore is a Store for workers in the same process.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: make(map[string]State)}
+}
+
+// Get returns the State of key.
+func (m *MemoryStore) Get(ctx context.Context, key string) (State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.states[key], nil
+}
+
+// Set stores the State of key. The zero State removes the key.
+func (m *MemoryStore) Set(ctx context.Context, key string, s State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s == (State{}) {
+		delete(m.states, key)
+		return nil
+	}
+	m.states[key] = s
+	return nil
+}
@@ -0,0 +1,49 @@
+// Package backofftest provides helpers for tests of custom Intervals and of
+// code that builds policies, for example from configuration files.
+package backofftest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// CheckSchedule compares the first len(want) intervals of iv with want and
+// returns an error describing the first interval that differs by more than
+// tolerance.
+func CheckSchedule(iv backoff.Intervals, want []time.Duration, tolerance time.Duration) error {
+	for _, e := range backoff.Schedule(iv, len(want)) {
+		diff := e.Interval - want[e.Iteration]
+		if diff > tolerance || -diff > tolerance {
+			if tolerance == 0 {
+				return fmt.Errorf("interval %d is %s, want %s", e.Iteration, e.Interval, want[e.Iteration])
+			}
+			return fmt.Errorf("interval %d is %s, want %s +/- %s", e.Iteration, e.Interval, want[e.Iteration], tolerance)
+		}
+	}
+	return nil
+}
+
+// AssertSchedule reports a test error unless the first len(want) intervals of
+// iv equal want:
+//
+//	backofftest.AssertSchedule(t, iv, []time.Duration{time.Second, 2 * time.Second})
+//
+// It returns true if the schedule matches.
+func AssertSchedule(t testing.TB, iv backoff.Intervals, want []time.Duration) bool {
+	t.Helper()
+	return AssertScheduleWithin(t, iv, want, 0)
+}
+
+// AssertScheduleWithin is like AssertSchedule but allows each interval to
+// differ from want by up to tolerance, for series with jitter.
+func AssertScheduleWithin(t testing.TB, iv backoff.Intervals, want []time.Duration, tolerance time.Duration) bool {
+	t.Helper()
+	if err := CheckSchedule(iv, want, tolerance); err != nil {
+		t.Error(err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package backofftest_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backofftest"
+)
+
+// recordingT records test errors instead of failing the test
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Error(args ...interface{}) {
+	r.errors = append(r.errors, args[0].(error).Error())
+}
+
+func Test_AssertSchedule(t *testing.T) {
+	jitter := backoff.ExponentialJitter{
+		Exponential: backoff.DefaultBinaryExponential(),
+		JitterMax:   100 * time.Millisecond,
+		Rand:        rand.New(rand.NewSource(1)),
+	}
+
+	cases := map[string]struct {
+		iv         backoff.Intervals
+		want       []time.Duration
+		tolerance  time.Duration
+		wantErrors []string
+	}{
+		"Exact match": {
+			iv:   backoff.DefaultBinaryExponential(),
+			want: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second},
+		},
+		"Mismatch": {
+			iv:         backoff.DefaultBinaryExponential(),
+			want:       []time.Duration{500 * time.Millisecond, 2 * time.Second},
+			wantErrors: []string{"interval 1 is 1s, want 2s"},
+		},
+		"Jitter within tolerance": {
+			iv:        jitter,
+			want:      []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second},
+			tolerance: 100 * time.Millisecond,
+		},
+		"Jitter beyond tolerance": {
+			iv:         backoff.DefaultBinaryExponential(),
+			want:       []time.Duration{time.Second},
+			tolerance:  100 * time.Millisecond,
+			wantErrors: []string{"interval 0 is 500ms, want 1s +/- 100ms"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			rt := &recordingT{TB: t}
+			ok := backofftest.AssertScheduleWithin(rt, tc.iv, tc.want, tc.tolerance)
+			assert.Equal(t, tc.wantErrors == nil, ok)
+			assert.Equal(t, tc.wantErrors, rt.errors)
+		})
+	}
+}
+
+func Test_AssertSchedule_Exact(t *testing.T) {
+	backofftest.AssertSchedule(t, backoff.DefaultBinaryExponential(), []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second,
+	})
+}
@@ -0,0 +1,193 @@
+// Package backoffws keeps message stream connections, like WebSockets, alive by
+// reconnecting with backoff. It does not depend on a WebSocket library: the
+// connection is dialed and read by user provided functions.
+package backoffws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// State is the state of a Reconnector.
+type State int
+
+const (
+	// Connecting means a dial is in progress
+	Connecting State = iota
+	// Connected means the read loop is running
+	Connected
+	// Waiting means the Reconnector is backing off before the next dial
+	Waiting
+	// Stopped means Run returned
+	Stopped
+)
+
+func (s State) String() string {
+	switch s {
+	case Connecting:
+		return "connecting"
+	case Connected:
+		return "connected"
+	case Waiting:
+		return "waiting"
+	case Stopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+// Reconnector dials a connection of type C and runs a read loop on it. When the
+// dial or the read loop fails, it reconnects after a backoff pause. The backoff
+// is reset once a connection stays up for StableAfter.
+type Reconnector[C any] struct {
+	// Dial establishes a new connection
+	Dial func(ctx context.Context) (C, error)
+	// ReadLoop reads from the connection until it fails or ctx is done. It
+	// should close the connection before returning.
+	ReadLoop func(ctx context.Context, conn C) error
+	// Intervals computes the pauses between reconnects. Defaults to
+	// DefaultBinaryExponentialJitter.
+	Intervals backoff.Intervals
+	// StableAfter is how long a connection must stay up to reset the backoff
+	StableAfter time.Duration
+	// OnStateChange is called on every state change with the error that
+	// caused it, if any. It may be nil.
+	OnStateChange func(state State, err error)
+
+	mu     sync.Mutex
+	resume chan struct{} // non-nil while paused
+	kick   chan struct{}
+}
+
+// Run keeps a connection alive until ctx is done and returns the context
+// error.
+func (r *Reconnector[C]) Run(ctx context.Context) error {
+	iv := r.intervals()
+	var (
+		i    int8
+		wait time.Duration
+	)
+	kick := r.kicks()
+	// a RetryNow from before Run does not skip the first pause
+	select {
+	case <-kick:
+	default:
+	}
+	defer r.notify(Stopped, nil)
+	for {
+		if err := r.waitResumed(ctx); err != nil {
+			return err
+		}
+		r.notify(Connecting, nil)
+		conn, err := r.Dial(ctx)
+		if err == nil {
+			r.notify(Connected, nil)
+			start := time.Now()
+			err = r.ReadLoop(ctx, conn)
+			if time.Since(start) >= r.StableAfter {
+				i, wait = 0, 0
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		wait = iv.Next(i, wait)
+		if i < backoff.InfiniteTries {
+			i++
+		}
+		r.notify(Waiting, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-kick:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// Pause stops the Reconnector from dialing until Resume is called, like
+// backoff.Loop.Pause. A connection that is up is not closed.
+func (r *Reconnector[C]) Pause() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.resume == nil {
+		r.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused Reconnector dial again.
+func (r *Reconnector[C]) Resume() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.resume != nil {
+		close(r.resume)
+		r.resume = nil
+	}
+}
+
+// Paused reports if the Reconnector is paused.
+func (r *Reconnector[C]) Paused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resume != nil
+}
+
+// RetryNow skips the remaining backoff pause so the next dial starts
+// immediately. If called while connected, the pause after the connection
+// drops is skipped. RetryNow does not resume a paused Reconnector.
+func (r *Reconnector[C]) RetryNow() {
+	select {
+	case r.kicks() <- struct{}{}:
+	default:
+		// a retry is already pending
+	}
+}
+
+// kicks returns the channel of RetryNow
+func (r *Reconnector[C]) kicks() chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.kick == nil {
+		r.kick = make(chan struct{}, 1)
+	}
+	return r.kick
+}
+
+// waitResumed blocks while the Reconnector is paused
+func (r *Reconnector[C]) waitResumed(ctx context.Context) error {
+	r.mu.Lock()
+	resume := r.resume
+	r.mu.Unlock()
+	if resume == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resume:
+		return nil
+	}
+}
+
+func (r *Reconnector[C]) intervals() backoff.Intervals {
+	if r.Intervals != nil {
+		return r.Intervals
+	}
+	iv, err := backoff.DefaultBinaryExponentialJitter()
+	if err != nil {
+		return backoff.DefaultBinaryExponential()
+	}
+	return iv
+}
+
+func (r *Reconnector[C]) notify(state State, err error) {
+	if r.OnStateChange != nil {
+		r.OnStateChange(state, err)
+	}
+}
@@ -0,0 +1,122 @@
+package backoffws_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffws"
+)
+
+type conn struct {
+	id int
+}
+
+// records the pause before each dial
+type recordingIntervals struct {
+	iv    backoff.Intervals
+	calls []int8
+}
+
+func (r *recordingIntervals) Next(i int8, last time.Duration) time.Duration {
+	r.calls = append(r.calls, i)
+	return r.iv.Next(i, last)
+}
+
+func Test_Reconnector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dialErr := errors.New("dial failed")
+	readErr := errors.New("connection reset")
+	dials := 0
+	iv := &recordingIntervals{iv: backoff.Exponential{
+		Base:    2 * time.Millisecond,
+		Unit:    time.Millisecond,
+		Initial: time.Millisecond,
+		Max:     10 * time.Millisecond,
+	}}
+
+	var states []string
+	r := &backoffws.Reconnector[*conn]{
+		Dial: func(ctx context.Context) (*conn, error) {
+			dials++
+			// fail the first two dials
+			if dials <= 2 {
+				return nil, dialErr
+			}
+			return &conn{id: dials}, nil
+		},
+		ReadLoop: func(ctx context.Context, c *conn) error {
+			switch c.id {
+			case 3:
+				// stable connection resets the backoff
+				time.Sleep(20 * time.Millisecond)
+				return readErr
+			case 4:
+				return readErr
+			}
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		Intervals:   iv,
+		StableAfter: 10 * time.Millisecond,
+		OnStateChange: func(state backoffws.State, err error) {
+			s := state.String()
+			if err != nil {
+				s += ":" + err.Error()
+			}
+			states = append(states, s)
+		},
+	}
+
+	err := r.Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []int8{0, 1, 0, 1}, iv.calls)
+	assert.Equal(t, []string{
+		"connecting", "waiting:dial failed",
+		"connecting", "waiting:dial failed",
+		"connecting", "connected", "waiting:connection reset",
+		"connecting", "connected", "waiting:connection reset",
+		"connecting", "connected",
+		"stopped",
+	}, states)
+}
+
+func Test_Reconnector_Control(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	dials := make(chan struct{}, 10)
+	r := &backoffws.Reconnector[*conn]{
+		Dial: func(ctx context.Context) (*conn, error) {
+			dials <- struct{}{}
+			return nil, errors.New("dial failed")
+		},
+		ReadLoop:  func(ctx context.Context, c *conn) error { return nil },
+		Intervals: backoff.Exponential{Base: 1, Unit: 1, Initial: time.Hour, Max: time.Hour},
+	}
+	// a RetryNow before Run does not skip the first pause
+	r.RetryNow()
+	r.Pause()
+	assert.True(t, r.Paused())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, dials, "paused")
+	r.Resume()
+	<-dials
+	time.Sleep(10 * time.Millisecond)
+	assert.Empty(t, dials, "backing off for an hour")
+
+	r.RetryNow()
+	<-dials
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
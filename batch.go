@@ -0,0 +1,42 @@
+package backoff
+
+import (
+	"context"
+)
+
+// BatchPartiallyFailed is the attempt error recorded when a BatchFunc reports
+// failed items without an error
+const BatchPartiallyFailed = Error("batch partially failed")
+
+// BatchFunc processes a batch of items. It returns the items that failed and
+// should be retried. A non-nil error with no failed items means the whole
+// batch failed.
+type BatchFunc[T any] func(ctx context.Context, items []T) (failed []T, err error)
+
+// RetryBatch calls fn with items and then, using TryErr with b, retries only
+// the failed subset of the batch each round. It returns the items that still
+// failed when the loop gave up, or nil on success, along with the TryErr
+// error.
+func RetryBatch[T any](ctx context.Context, b *Backoff, tries int8, items []T, fn BatchFunc[T]) ([]T, error) {
+	pending := items
+	err := b.TryErr(ctx, tries, func(ctx context.Context) error {
+		failed, err := fn(ctx, pending)
+		if len(failed) > 0 {
+			pending = failed
+			if err == nil {
+				err = BatchPartiallyFailed
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		pending = nil
+		return nil
+	})
+	if err != nil && len(pending) == 0 {
+		// stopped before the first round
+		pending = items
+	}
+	return pending, err
+}
@@ -0,0 +1,74 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_RetryBatch(t *testing.T) {
+	batchErr := errors.New("bulk request failed")
+
+	cases := map[string]struct {
+		// number of rounds each item fails before succeeding, -1 fails forever
+		failRounds map[int]int
+		batchFails int // rounds that fail for the whole batch
+		wantFailed []int
+		wantErr    error
+		wantCalls  [][]int
+	}{
+		"All succeed": {
+			failRounds: map[int]int{},
+			wantCalls:  [][]int{{1, 2, 3}},
+		},
+		"Retry only failed items": {
+			failRounds: map[int]int{2: 1, 3: 2},
+			wantCalls:  [][]int{{1, 2, 3}, {2, 3}, {3}},
+		},
+		"Whole batch failure retries all pending": {
+			failRounds: map[int]int{},
+			batchFails: 1,
+			wantCalls:  [][]int{{1, 2, 3}, {1, 2, 3}},
+		},
+		"Return items that never succeed": {
+			failRounds: map[int]int{1: -1, 3: 1},
+			wantFailed: []int{1},
+			wantErr:    backoff.AllTriesFailed,
+			wantCalls:  [][]int{{1, 2, 3}, {1, 3}, {1}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var calls [][]int
+			bo := backoff.NewBackoff(shortInterval)
+			failed, err := backoff.RetryBatch(ctx, bo, 3, []int{1, 2, 3}, func(ctx context.Context, items []int) ([]int, error) {
+				round := len(calls)
+				calls = append(calls, items)
+				if round < tc.batchFails {
+					return nil, batchErr
+				}
+				var failed []int
+				for _, item := range items {
+					n, ok := tc.failRounds[item]
+					if ok && (n < 0 || round < n) {
+						failed = append(failed, item)
+					}
+				}
+				return failed, nil
+			})
+
+			assert.Equal(t, tc.wantFailed, failed)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhomel/backoff"
+)
+
+// a CAS style loop that succeeds on the 10th attempt
+func BenchmarkTry_Immediate(b *testing.B) {
+	ctx := context.Background()
+	policy := backoff.Immediate(10)
+	bo := policy.NewBackoff()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		calls := 0
+		_ = bo.Try(ctx, policy.Tries, func(ctx context.Context) bool {
+			calls++
+			return calls == 10
+		})
+	}
+}
+
+func BenchmarkTryErr_Immediate(b *testing.B) {
+	ctx := context.Background()
+	policy := backoff.Immediate(10)
+	bo := policy.NewBackoff()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		calls := 0
+		_ = bo.TryErr(ctx, policy.Tries, func(ctx context.Context) error {
+			calls++
+			if calls == 10 {
+				return nil
+			}
+			return backoff.AttemptFailed
+		})
+	}
+}
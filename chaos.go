@@ -0,0 +1,44 @@
+package backoff
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// ChaosFailure is the error of a successful attempt converted into a failure by
+// WithChaos.
+const ChaosFailure = Error("chaos: synthetic failure")
+
+type chaos struct {
+	failRate float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// WithChaos randomly converts successful attempts into failures with the
+// probability failRate (0 to 1). The converted attempts fail with ChaosFailure.
+// This is meant to validate retry handling and alerting paths in staging and
+// should never be enabled in production. The seed makes runs reproducible.
+func WithChaos(failRate float64, seed int64) Options {
+	return func(bo *Backoff) {
+		bo.chaos = &chaos{
+			failRate: failRate,
+			rand:     rand.New(rand.NewSource(seed)),
+		}
+	}
+}
+
+// inject returns ChaosFailure instead of a nil err at the configured rate. It
+// is safe to call on a nil chaos.
+func (c *chaos) inject(err error) error {
+	if c == nil || err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rand.Float64() < c.failRate {
+		return ChaosFailure
+	}
+	return nil
+}
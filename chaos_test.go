@@ -0,0 +1,70 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithChaos(t *testing.T) {
+	cases := map[string]struct {
+		failRate     float64
+		tries        int8
+		wantErr      error
+		wantAttempts int
+	}{
+		"Never fail": {
+			failRate:     0,
+			tries:        3,
+			wantErr:      nil,
+			wantAttempts: 1,
+		},
+		"Always fail": {
+			failRate:     1,
+			tries:        3,
+			wantErr:      backoff.ChaosFailure,
+			wantAttempts: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := backoff.NewBackoff(shortInterval, backoff.WithChaos(tc.failRate, 1))
+			attempts := 0
+			err := bo.TryErr(ctx, tc.tries, func(ctx context.Context) error {
+				attempts++
+				return nil
+			})
+
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tc.wantErr))
+			}
+			assert.Equal(t, tc.wantAttempts, attempts)
+		})
+	}
+}
+
+func Test_WithChaos_SameSeedIsReproducible(t *testing.T) {
+	run := func() int {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		bo := backoff.NewBackoff(shortInterval, backoff.WithChaos(0.5, 42))
+		attempts := 0
+		_ = bo.Try(ctx, 10, func(ctx context.Context) bool {
+			attempts++
+			return true
+		})
+		return attempts
+	}
+	assert.Equal(t, run(), run())
+}
@@ -0,0 +1,117 @@
+package backoff
+
+import "time"
+
+// Class is a category of attempt errors assigned by the classifier provided
+// with WithClassifier, for example "dns" or "auth". The empty Class is the
+// default for unclassified errors.
+type Class string
+
+// WithClassifier assigns a Class to the error of each failed attempt. Other
+// options like WithFreeClass change the behavior of the loop per Class.
+func WithClassifier(fn func(err error) Class) Options {
+	return func(bo *Backoff) {
+		bo.classifier = fn
+	}
+}
+
+// WithFreeClass marks failures of the given Class as free: they do not consume
+// a try. This suits failures that are expected while a dependency warms up,
+// like DNS NXDOMAIN during service startup, or that are fixed before the next
+// attempt, like an expired token that triggers a refresh. The pause after a
+// free failure follows iv, or the regular series if iv is nil. Since free
+// failures can repeat forever, the context should have a deadline.
+func WithFreeClass(class Class, iv Intervals) Options {
+	return func(bo *Backoff) {
+		if bo.freeClasses == nil {
+			bo.freeClasses = make(map[Class]bool)
+		}
+		bo.freeClasses[class] = true
+		if iv != nil {
+			WithClassIntervals(map[Class]Intervals{class: iv})(bo)
+		}
+	}
+}
+
+// WithClassIntervals uses a distinct Intervals series for the pauses after
+// failures of each Class, for example long waits for rate limiting and short
+// constant waits for connection resets. Each series advances only with
+// failures of its Class. Classes without an entry use the regular series.
+func WithClassIntervals(intervals map[Class]Intervals) Options {
+	return func(bo *Backoff) {
+		if bo.classIntervals == nil {
+			bo.classIntervals = make(map[Class]Intervals)
+		}
+		for class, iv := range intervals {
+			bo.classIntervals[class] = iv
+		}
+	}
+}
+
+// WithFailureWeights sets how much of a try the failures of each Class
+// consume. Classes without an entry consume a full try (weight 1). For example
+// with a weight of 0.5 for timeouts, `tries` 3 allows 6 attempts that time out
+// but only 3 attempts that fail otherwise. This tolerates more flaky but
+// recoverable failures while keeping the loop bounded: weights below
+// MinFailureWeight, including zero and negative weights, are raised to it. Use
+// WithFreeClass for failures that should not consume tries at all.
+func WithFailureWeights(weights map[Class]float64) Options {
+	clamped := make(map[Class]float64, len(weights))
+	for class, w := range weights {
+		if w < MinFailureWeight {
+			w = MinFailureWeight
+		}
+		clamped[class] = w
+	}
+	return func(bo *Backoff) {
+		bo.weights = clamped
+	}
+}
+
+// MinFailureWeight is the smallest weight of WithFailureWeights, so a try
+// allows at most 100 failures of a class
+const MinFailureWeight = 0.01
+
+// weight returns the try credit consumed by a failure of class
+func (b *Backoff) weight(class Class) float64 {
+	if w, ok := b.weights[class]; ok {
+		return w
+	}
+	return 1
+}
+
+func (b *Backoff) classify(err error) Class {
+	if b.classifier == nil {
+		return ""
+	}
+	return b.classifier(err)
+}
+
+// series tracks the position in an Intervals series
+type series struct {
+	intervals Intervals
+	i         int8
+	last      time.Duration
+}
+
+func (s *series) next() time.Duration {
+	s.last = s.intervals.Next(s.i, s.last)
+	if s.i < InfiniteTries {
+		s.i++
+	}
+	return s.last
+}
+
+// classNext returns the next interval of the series of class, creating the
+// series and the map as needed
+func classNext(classes map[Class]*series, class Class, iv Intervals) (time.Duration, map[Class]*series) {
+	if classes == nil {
+		classes = make(map[Class]*series)
+	}
+	s, ok := classes[class]
+	if !ok {
+		s = &series{intervals: iv}
+		classes[class] = s
+	}
+	return s.next(), classes
+}
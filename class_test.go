@@ -0,0 +1,245 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+var errNXDomain = errors.New("no such host")
+
+func classifyDNS(err error) backoff.Class {
+	if errors.Is(err, errNXDomain) {
+		return "dns"
+	}
+	return ""
+}
+
+func Test_WithFreeClass(t *testing.T) {
+	constant := backoff.Exponential{Base: 1, Unit: 1, Initial: 3 * time.Millisecond, Max: 3 * time.Millisecond}
+	other := errors.New("other")
+
+	cases := map[string]struct {
+		freeIv       backoff.Intervals
+		errs         []error
+		tries        int8
+		wantErr      bool
+		wantAttempts int
+		wantWaits    []time.Duration
+	}{
+		"Free failures do not consume tries": {
+			freeIv:       constant,
+			errs:         []error{errNXDomain, errNXDomain, errNXDomain, other},
+			tries:        2,
+			wantAttempts: 5,
+			wantWaits: []time.Duration{
+				3 * time.Millisecond,
+				3 * time.Millisecond,
+				3 * time.Millisecond,
+				1 * time.Millisecond,
+			},
+		},
+		"Free failures use the regular series without intervals": {
+			errs:         []error{errNXDomain, other, errNXDomain},
+			tries:        2,
+			wantAttempts: 4,
+			wantWaits: []time.Duration{
+				1 * time.Millisecond,
+				1 * time.Millisecond,
+				2 * time.Millisecond,
+			},
+		},
+		"Other failures consume tries": {
+			freeIv:       constant,
+			errs:         []error{other, errNXDomain, other},
+			tries:        2,
+			wantErr:      true,
+			wantAttempts: 3,
+			wantWaits: []time.Duration{
+				1 * time.Millisecond,
+				3 * time.Millisecond,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			rec := backoff.NewRecorder(10)
+			bo := backoff.NewBackoff(shortInterval,
+				backoff.WithRecorder(rec),
+				backoff.WithClassifier(classifyDNS),
+				backoff.WithFreeClass("dns", tc.freeIv),
+			)
+			attempts := 0
+			next := failWith(tc.errs...)
+			err := bo.TryErr(ctx, tc.tries, func(ctx context.Context) error {
+				attempts++
+				return next(ctx)
+			})
+
+			assert.Equal(t, tc.wantErr, err != nil, "got %v", err)
+			assert.Equal(t, tc.wantAttempts, attempts)
+			var waits []time.Duration
+			for _, r := range rec.Snapshot() {
+				if r.Wait > 0 {
+					waits = append(waits, r.Wait)
+				}
+			}
+			assert.Equal(t, tc.wantWaits, waits)
+		})
+	}
+}
+
+func Test_WithClassIntervals(t *testing.T) {
+	errRateLimited := errors.New("429")
+	errReset := errors.New("connection reset")
+	classify := func(err error) backoff.Class {
+		switch {
+		case errors.Is(err, errRateLimited):
+			return "ratelimit"
+		case errors.Is(err, errReset):
+			return "reset"
+		}
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rec := backoff.NewRecorder(10)
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithRecorder(rec),
+		backoff.WithClassifier(classify),
+		backoff.WithClassIntervals(map[backoff.Class]backoff.Intervals{
+			"ratelimit": backoff.Exponential{Base: 3 * time.Millisecond, Unit: time.Millisecond, Initial: 5 * time.Millisecond, Max: 50 * time.Millisecond},
+			"reset":     backoff.Exponential{Base: 1, Unit: 1, Initial: 2 * time.Millisecond, Max: 2 * time.Millisecond},
+		}),
+	)
+	err := bo.TryErr(ctx, 10, failWith(
+		errRateLimited,
+		errReset,
+		errRateLimited,
+		errors.New("other"),
+		errReset,
+	))
+	assert.NoError(t, err)
+
+	var waits []time.Duration
+	for _, r := range rec.Snapshot() {
+		if r.Wait > 0 {
+			waits = append(waits, r.Wait)
+		}
+	}
+	assert.Equal(t, []time.Duration{
+		5 * time.Millisecond,
+		2 * time.Millisecond,
+		15 * time.Millisecond,
+		8 * time.Millisecond,
+		2 * time.Millisecond,
+	}, waits)
+}
+
+func Test_WithFailureWeights(t *testing.T) {
+	timeout := errors.New("timeout")
+	refused := errors.New("connection refused")
+
+	cases := map[string]struct {
+		errs      []error
+		wantCalls int
+	}{
+		"Soft failures use half a try": {
+			errs:      []error{timeout, timeout, timeout, timeout, timeout, timeout, timeout},
+			wantCalls: 6,
+		},
+		"Hard failures use a full try": {
+			errs:      []error{refused, refused, refused, refused},
+			wantCalls: 3,
+		},
+		"Mixed failures": {
+			errs:      []error{timeout, refused, timeout, refused, refused},
+			wantCalls: 4,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := backoff.NewBackoff(shortInterval,
+				backoff.WithClassifier(func(err error) backoff.Class {
+					if errors.Is(err, timeout) {
+						return "soft"
+					}
+					return ""
+				}),
+				backoff.WithFailureWeights(map[backoff.Class]float64{"soft": 0.5}),
+			)
+			calls := 0
+			err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+
+			assert.ErrorIs(t, err, backoff.AllTriesFailed)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
+
+func Test_WithFreeClass_PerCallOption(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	classify := func(err error) backoff.Class {
+		if errors.Is(err, errNXDomain) {
+			return "dns"
+		}
+		return "other"
+	}
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithClassifier(classify),
+		backoff.WithFreeClass("dns", nil))
+	err := bo.TryErr(ctx, 2, failWith(assert.AnError), backoff.WithFreeClass("other", shortInterval))
+	assert.NoError(t, err)
+
+	// the per call option does not change bo
+	attempts := 0
+	err = bo.TryErr(ctx, 2, func(ctx context.Context) error {
+		attempts++
+		return assert.AnError
+	})
+	assert.True(t, errors.Is(err, backoff.AllTriesFailed))
+	assert.Equal(t, 2, attempts)
+}
+
+func Test_WithFailureWeights_Clamped(t *testing.T) {
+	for name, weight := range map[string]float64{"zero": 0, "negative": -1} {
+		weight := weight
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := backoff.NewBackoff(backoff.IntervalsFunc(func(i int, last time.Duration) time.Duration { return 0 }),
+				backoff.WithClassifier(func(err error) backoff.Class { return "soft" }),
+				backoff.WithFailureWeights(map[backoff.Class]float64{"soft": weight}),
+			)
+			calls := 0
+			err := bo.TryErr(ctx, 1, func(ctx context.Context) error {
+				calls++
+				return assert.AnError
+			})
+
+			assert.ErrorIs(t, err, backoff.AllTriesFailed)
+			// 1 / MinFailureWeight, up to rounding of the try credits
+			assert.InDelta(t, 100, calls, 1)
+		})
+	}
+}
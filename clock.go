@@ -0,0 +1,123 @@
+package backoff
+
+import "time"
+
+// ClockJump describes a backoff pause during which the wall clock and the
+// monotonic clock disagreed, for example because of an NTP step or because the
+// host was suspended. It is passed to the function provided with
+// WithClockAudit.
+type ClockJump struct {
+	// Attempt is the number of the attempt that preceded the pause
+	Attempt int
+	// Pause is the intended pause
+	Pause time.Duration
+	// Monotonic is the pause measured with the monotonic clock
+	Monotonic time.Duration
+	// Wall is the pause measured with the wall clock
+	Wall time.Duration
+}
+
+// Skew is the difference between the wall clock and the monotonic clock
+// measurements. It is positive when the wall clock jumped forward.
+func (j ClockJump) Skew() time.Duration {
+	return j.Wall - j.Monotonic
+}
+
+// WithClockAudit measures every backoff pause with both the monotonic and the
+// wall clock and calls fn when the two measurements differ by more than
+// tolerance. Use it to explain schedules that look wrong in logs of laptops
+// and VMs that were suspended or had their clock stepped mid backoff.
+func WithClockAudit(tolerance time.Duration, fn func(ClockJump)) Options {
+	return func(bo *Backoff) {
+		bo.clockAudit = &clockAudit{
+			tolerance: tolerance,
+			fn:        fn,
+			read:      readClock,
+		}
+	}
+}
+
+// clockStart anchors the monotonic readings
+var clockStart = time.Now()
+
+// readClock returns a monotonic reading and the wall clock time
+func readClock() (time.Duration, time.Time) {
+	now := time.Now()
+	return now.Sub(clockStart), now.Round(0)
+}
+
+type clockAudit struct {
+	tolerance time.Duration
+	fn        func(ClockJump)
+	read      func() (time.Duration, time.Time)
+}
+
+type clockMark struct {
+	mono time.Duration
+	wall time.Time
+}
+
+// begin takes the readings at the start of a pause
+func (c *clockAudit) begin() clockMark {
+	if c == nil {
+		return clockMark{}
+	}
+	mono, wall := c.read()
+	return clockMark{mono: mono, wall: wall}
+}
+
+// end compares the readings at the end of a pause with the mark
+func (c *clockAudit) end(m clockMark, attempt int, pause time.Duration) {
+	if c == nil || c.fn == nil {
+		return
+	}
+	mono, wall := c.read()
+	jump := ClockJump{
+		Attempt:   attempt,
+		Pause:     pause,
+		Monotonic: mono - m.mono,
+		Wall:      wall.Sub(m.wall),
+	}
+	skew := jump.Skew()
+	if skew > c.tolerance || -skew > c.tolerance {
+		c.fn(jump)
+	}
+}
+
+// wallClockStep is how often a wall clock wait re-checks the wall clock
+const wallClockStep = time.Second
+
+// WithWallClockWaits makes backoff pauses end at the intended wall clock time.
+// By default pauses are measured with the monotonic clock, which on most
+// systems stops while the host is suspended, so a 10 minute pause that spans a
+// suspend ends late by the suspended time. A wall clock wait re-checks the
+// intended wake time every second and ends as soon as it has passed, which
+// gives calendar accurate schedules at the cost of following clock steps.
+func WithWallClockWaits() Options {
+	return func(bo *Backoff) {
+		bo.afterFunc = wallClockAfter(time.Now, wallClockStep)
+	}
+}
+
+// wallClockAfter returns an after func that fires once the wall clock reaches
+// the intended wake time, checking every step
+func wallClockAfter(now func() time.Time, step time.Duration) after {
+	return func(d time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		wake := now().Round(0).Add(d)
+		go func() {
+			for {
+				remaining := wake.Sub(now().Round(0))
+				if remaining <= 0 {
+					ch <- now()
+					return
+				}
+				if remaining > step {
+					remaining = step
+				}
+				time.Sleep(remaining)
+			}
+		}()
+		return ch
+	}
+}
@@ -0,0 +1,128 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_try_ClockAudit(t *testing.T) {
+	cases := map[string]struct {
+		jump      time.Duration
+		wantJumps []ClockJump
+	}{
+		"Clocks agree": {
+			jump: 10 * time.Millisecond,
+		},
+		"Wall clock steps forward": {
+			jump: time.Hour,
+			wantJumps: []ClockJump{
+				{Attempt: 2, Pause: time.Second, Monotonic: time.Millisecond, Wall: time.Millisecond + time.Hour},
+			},
+		},
+		"Wall clock steps back": {
+			jump: -time.Minute,
+			wantJumps: []ClockJump{
+				{Attempt: 2, Pause: time.Second, Monotonic: time.Millisecond, Wall: time.Millisecond - time.Minute},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var jumps []ClockJump
+			b := NewBackoff(DefaultBinaryExponential(),
+				withAfterFunc(immediateAfterFunc),
+				WithClockAudit(time.Second, func(j ClockJump) {
+					jumps = append(jumps, j)
+				}))
+			// each reading advances both clocks by 1ms, the wall clock jumps
+			// during the second pause
+			reads := 0
+			var mono time.Duration
+			wall := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			b.clockAudit.read = func() (time.Duration, time.Time) {
+				reads++
+				mono += time.Millisecond
+				wall = wall.Add(time.Millisecond)
+				if reads == 4 {
+					wall = wall.Add(tc.jump)
+				}
+				return mono, wall
+			}
+
+			err := b.Try(context.Background(), 4, func(ctx context.Context) bool {
+				return false
+			})
+			assert.Equal(t, AllTriesFailed, err)
+			assert.Equal(t, 6, reads)
+			assert.Equal(t, tc.wantJumps, jumps)
+		})
+	}
+}
+
+func Test_ClockJump_Skew(t *testing.T) {
+	j := ClockJump{Monotonic: time.Second, Wall: time.Minute}
+	assert.Equal(t, 59*time.Second, j.Skew())
+}
+
+func Test_wallClockAfter(t *testing.T) {
+	cases := map[string]struct {
+		jump        time.Duration
+		wantAtLeast time.Duration
+		wantAtMost  time.Duration
+	}{
+		"Waits the full pause": {
+			wantAtLeast: 20 * time.Millisecond,
+			wantAtMost:  time.Second,
+		},
+		"Wakes early after the wall clock jumps past the wake time": {
+			jump:       time.Hour,
+			wantAtMost: 500 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			reads := 0
+			now := func() time.Time {
+				reads++
+				if reads > 1 {
+					// simulate a suspend after the wait started
+					return time.Now().Add(tc.jump)
+				}
+				return time.Now()
+			}
+			wait := time.Second
+			if tc.jump == 0 {
+				wait = 20 * time.Millisecond
+			}
+			start := time.Now()
+			<-wallClockAfter(now, time.Millisecond)(wait)
+			took := time.Since(start)
+			assert.GreaterOrEqual(t, took, tc.wantAtLeast)
+			assert.Less(t, took, tc.wantAtMost)
+		})
+	}
+}
+
+func Test_try_WallClockWaits(t *testing.T) {
+	iv := Exponential{
+		Base:    2 * time.Millisecond,
+		Unit:    time.Millisecond,
+		Initial: time.Millisecond,
+		Max:     20 * time.Millisecond,
+	}
+	b := NewBackoff(iv, WithWallClockWaits())
+	calls := 0
+	err := b.Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return calls == 3
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
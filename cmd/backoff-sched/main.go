@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/rhomel/backoff"
+	"github.com/rhomel/backoff/backoffsim"
+)
+
+// backoff-sched prints the schedule of an exponential series.
+//
+// Example:
+//
+//	$ backoff-sched -base 3s -initial 1s -max 30s -n 5
+//	ITERATION  INTERVAL  CUMULATIVE
+//	0          1s        1s
+//	1          3s        4s
+//	2          9s        13s
+//	3          27s       40s
+//	4          30s       1m10s
+//
+// With -simulate it simulates Try calls of the series against a failure model
+// instead, see parseModel for the models:
+//
+//	$ backoff-sched -simulate fail-prob:0.3 -tries 4 -attempt 100ms
+
+func main() {
+	var (
+		def = backoff.DefaultBinaryExponential()
+
+		base    = flag.Duration("base", def.Base, "exponential base")
+		unit    = flag.Duration("unit", def.Unit, "unit of the base")
+		initial = flag.Duration("initial", def.Initial, "initial interval")
+		maxIv   = flag.Duration("max", def.Max, "maximum interval")
+		jitter  = flag.Duration("jitter", 0, "maximum random jitter added or subtracted")
+		seed    = flag.Int64("seed", time.Now().UnixNano(), "seed for the jitter")
+		n       = flag.Int("n", 10, "number of intervals to print")
+		format  = flag.String("format", "text", "output format: text, csv or json")
+
+		simulate = flag.String("simulate", "", "failure model to simulate: fail-first:N, fail-prob:P or outage:START-END")
+		tries    = flag.Int("tries", 5, "tries of each simulated call")
+		runs     = flag.Int("runs", 1000, "number of simulated calls")
+		attempt  = flag.Duration("attempt", 0, "duration of each simulated attempt")
+	)
+	flag.Parse()
+
+	f, err := backoff.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var iv backoff.Intervals = backoff.Exponential{
+		Base:    *base,
+		Unit:    *unit,
+		Initial: *initial,
+		Max:     *maxIv,
+	}
+	if *jitter > 0 {
+		iv = backoff.ExponentialJitter{
+			Exponential: iv.(backoff.Exponential),
+			JitterMax:   *jitter,
+			Rand:        rand.New(rand.NewSource(*seed)),
+		}
+	}
+
+	if *simulate != "" {
+		model, err := parseModel(*simulate)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		printResult(os.Stdout, backoffsim.Simulate(backoffsim.Config{
+			Intervals:       iv,
+			Tries:           int8(*tries),
+			Model:           model,
+			AttemptDuration: *attempt,
+			Runs:            *runs,
+			Seed:            *seed,
+		}))
+		return
+	}
+
+	if err := backoff.DumpSchedule(iv, *n, os.Stdout, f); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rhomel/backoff/backoffsim"
+)
+
+// parseModel parses a failure model flag value:
+//
+//	fail-first:3      fail the first 3 attempts
+//	fail-prob:0.3     fail each attempt with probability 0.3
+//	outage:5s-30s     fail the attempts that start between 5s and 30s
+func parseModel(s string) (backoffsim.FailureModel, error) {
+	kind, params, _ := strings.Cut(s, ":")
+	switch kind {
+	case "fail-first":
+		n, err := strconv.Atoi(params)
+		if err != nil {
+			return nil, fmt.Errorf("fail-first model: %w", err)
+		}
+		return backoffsim.FailFirst(n), nil
+	case "fail-prob":
+		p, err := strconv.ParseFloat(params, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fail-prob model: %w", err)
+		}
+		return backoffsim.FailWithProbability(p), nil
+	case "outage":
+		from, to, ok := strings.Cut(params, "-")
+		if !ok {
+			return nil, fmt.Errorf("outage model: want start-end, got %q", params)
+		}
+		start, err := time.ParseDuration(from)
+		if err != nil {
+			return nil, fmt.Errorf("outage model: %w", err)
+		}
+		end, err := time.ParseDuration(to)
+		if err != nil {
+			return nil, fmt.Errorf("outage model: %w", err)
+		}
+		return backoffsim.Outage(start, end), nil
+	}
+	return nil, fmt.Errorf("unknown failure model %q", kind)
+}
+
+// printResult writes a human readable summary of a simulation
+func printResult(w io.Writer, r backoffsim.Result) {
+	fmt.Fprintf(w, "runs:        %d\n", r.Runs)
+	fmt.Fprintf(w, "success:     %.2f%%\n", 100*r.SuccessProbability())
+	fmt.Fprintf(w, "latency:     min %s  mean %s  p50 %s  p90 %s  p99 %s  max %s\n",
+		r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+	attempts := make([]int, 0, len(r.Attempts))
+	for n := range r.Attempts {
+		attempts = append(attempts, n)
+	}
+	sort.Ints(attempts)
+	fmt.Fprintln(w, "attempts:")
+	for _, n := range attempts {
+		fmt.Fprintf(w, "  %3d  %d\n", n, r.Attempts[n])
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff/backoffsim"
+)
+
+func Test_parseModel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	cases := map[string]struct {
+		model    string
+		wantErr  bool
+		attempt  int
+		at       time.Duration
+		wantFail bool
+	}{
+		"Fail first": {
+			model:    "fail-first:2",
+			attempt:  2,
+			wantFail: true,
+		},
+		"Fail first passes later attempts": {
+			model:   "fail-first:2",
+			attempt: 3,
+		},
+		"Fail probability": {
+			model:    "fail-prob:1",
+			attempt:  1,
+			wantFail: true,
+		},
+		"Outage": {
+			model:    "outage:1s-10s",
+			attempt:  2,
+			at:       5 * time.Second,
+			wantFail: true,
+		},
+		"After outage": {
+			model:   "outage:1s-10s",
+			attempt: 3,
+			at:      10 * time.Second,
+		},
+		"Invalid outage": {
+			model:   "outage:10s",
+			wantErr: true,
+		},
+		"Unknown model": {
+			model:   "flaky",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			model, err := parseModel(tc.model)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantFail, model(r, tc.attempt, tc.at))
+		})
+	}
+}
+
+func Test_printResult(t *testing.T) {
+	var buf bytes.Buffer
+	printResult(&buf, backoffsim.Result{
+		Runs:      4,
+		Successes: 3,
+		Attempts:  map[int]int{2: 1, 1: 3},
+	})
+	assert.Contains(t, buf.String(), "success:     75.00%")
+	assert.Contains(t, buf.String(), "    1  3\n    2  1\n")
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/rhomel/backoff"
+)
+
+// retry runs a command until it succeeds using the backoff package.
+//
+// Example:
+//
+//	$ retry --tries 5 --policy exponential:initial=1s,max=10s -- curl -f https://example.com
+//
+// Exit codes listed in --stop-on stop retrying immediately. If --retry-on is
+// set, only the listed exit codes are retried. When all tries fail, retry exits
+// with the exit code of the last run, 128 plus the signal number if it was
+// killed by a signal.
+
+func main() {
+	var (
+		tries      = flag.Int("tries", 5, "number of tries, 0 tries forever")
+		policy     = flag.String("policy", "default", "backoff policy: default, constant:<duration> or exponential:base=2s,unit=1s,initial=500ms,max=20s,jitter=0s")
+		maxElapsed = flag.Duration("max-elapsed", 0, "maximum total time, 0 for no limit")
+		retryOn    = flag.String("retry-on", "", "comma separated exit codes to retry, empty retries any failure")
+		stopOn     = flag.String("stop-on", "", "comma separated exit codes that stop retrying")
+		quiet      = flag.Bool("quiet", false, "do not log failed attempts")
+	)
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: retry [flags] -- command [args...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if *tries < 0 || *tries > backoff.InfiniteTries {
+		fail(2, fmt.Errorf("tries must be between 0 and %d", backoff.InfiniteTries))
+	}
+	iv, err := parsePolicy(*policy)
+	if err != nil {
+		fail(2, err)
+	}
+	classify, err := newClassifier(*retryOn, *stopOn)
+	if err != nil {
+		fail(2, err)
+	}
+
+	ctx := context.Background()
+	if *maxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *maxElapsed)
+		defer cancel()
+	}
+
+	n := int8(*tries)
+	if n == 0 {
+		n = backoff.InfiniteTries
+	}
+	attempt := 0
+	bo := backoff.NewBackoff(iv)
+	err = bo.TryErr(ctx, n, func(ctx context.Context) error {
+		attempt++
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err := classify(cmd.Run())
+		if err != nil && !*quiet {
+			fmt.Fprintf(os.Stderr, "retry: attempt %d: %v\n", attempt, err)
+		}
+		return err
+	})
+	if err != nil {
+		code := 1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitCode(exitErr)
+		}
+		fail(code, err)
+	}
+}
+
+func fail(code int, err error) {
+	fmt.Fprintln(os.Stderr, "retry:", err)
+	os.Exit(code)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rhomel/backoff"
+)
+
+// parsePolicy parses a policy flag value into an Intervals
+func parsePolicy(s string) (backoff.Intervals, error) {
+	kind, params, _ := strings.Cut(s, ":")
+	switch kind {
+	case "default":
+		return backoff.DefaultBinaryExponential(), nil
+	case "constant":
+		d, err := time.ParseDuration(params)
+		if err != nil {
+			return nil, fmt.Errorf("constant policy: %w", err)
+		}
+		return backoff.Exponential{Base: time.Second, Unit: time.Second, Initial: d, Max: d}, nil
+	case "exponential":
+		return parseExponential(params)
+	}
+	return nil, fmt.Errorf("unknown policy %q", kind)
+}
+
+func parseExponential(params string) (backoff.Intervals, error) {
+	e := backoff.DefaultBinaryExponential()
+	var jitter time.Duration
+	if params != "" {
+		for _, param := range strings.Split(params, ",") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok {
+				return nil, fmt.Errorf("exponential policy: invalid parameter %q", param)
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("exponential policy: %s: %w", key, err)
+			}
+			switch key {
+			case "base":
+				e.Base = d
+			case "unit":
+				e.Unit = d
+			case "initial":
+				e.Initial = d
+			case "max":
+				e.Max = d
+			case "jitter":
+				jitter = d
+			default:
+				return nil, fmt.Errorf("exponential policy: unknown parameter %q", key)
+			}
+		}
+	}
+	if e.Unit <= 0 {
+		return nil, errors.New("exponential policy: unit must be positive")
+	}
+	if jitter > 0 {
+		return backoff.ExponentialJitter{
+			Exponential: e,
+			JitterMax:   jitter,
+			Rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		}, nil
+	}
+	return e, nil
+}
+
+// newClassifier returns a function that converts the error of a command run
+// into the error reported to TryErr. Exit codes in stopOn are permanent. If
+// retryOn is not empty, exit codes not in retryOn are permanent too.
+func newClassifier(retryOn, stopOn string) (func(error) error, error) {
+	retry, err := parseCodes(retryOn)
+	if err != nil {
+		return nil, fmt.Errorf("retry-on: %w", err)
+	}
+	stop, err := parseCodes(stopOn)
+	if err != nil {
+		return nil, fmt.Errorf("stop-on: %w", err)
+	}
+	return func(err error) error {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			// nil or the command could not be started
+			return err
+		}
+		code := exitCode(exitErr)
+		if stop[code] || (len(retry) > 0 && !retry[code]) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, nil
+}
+
+// exitCode returns the exit code of a command run. A command killed by a
+// signal exits with 128 plus the signal number, as shells report it.
+func exitCode(exitErr *exec.ExitError) int {
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return exitErr.ExitCode()
+}
+
+func parseCodes(s string) (map[int]bool, error) {
+	codes := make(map[int]bool)
+	if s == "" {
+		return codes, nil
+	}
+	for _, c := range strings.Split(s, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(c))
+		if err != nil {
+			return nil, err
+		}
+		codes[code] = true
+	}
+	return codes, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_parsePolicy(t *testing.T) {
+	cases := map[string]struct {
+		policy  string
+		want    backoff.Intervals
+		wantErr bool
+	}{
+		"default": {
+			policy: "default",
+			want:   backoff.DefaultBinaryExponential(),
+		},
+		"constant": {
+			policy: "constant:2s",
+			want:   backoff.Exponential{Base: time.Second, Unit: time.Second, Initial: 2 * time.Second, Max: 2 * time.Second},
+		},
+		"exponential": {
+			policy: "exponential:base=3s,initial=1s,max=30s",
+			want:   backoff.Exponential{Base: 3 * time.Second, Unit: time.Second, Initial: time.Second, Max: 30 * time.Second},
+		},
+		"unknown policy": {
+			policy:  "linear:1s",
+			wantErr: true,
+		},
+		"unknown parameter": {
+			policy:  "exponential:factor=2s",
+			wantErr: true,
+		},
+		"invalid duration": {
+			policy:  "constant:soon",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			got, err := parsePolicy(tc.policy)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_newClassifier(t *testing.T) {
+	classify, err := newClassifier("1,2", "2")
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		command      string
+		wantAttempts int
+	}{
+		"Success": {
+			command:      "exit 0",
+			wantAttempts: 1,
+		},
+		"Retry on listed code": {
+			command:      "exit 1",
+			wantAttempts: 3,
+		},
+		"Stop on code": {
+			command:      "exit 2",
+			wantAttempts: 1,
+		},
+		"Stop on code not in retry list": {
+			command:      "exit 3",
+			wantAttempts: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			iv := backoff.Exponential{Base: time.Millisecond, Unit: time.Millisecond, Initial: time.Millisecond, Max: time.Millisecond}
+			attempts := 0
+			_ = backoff.NewBackoff(iv).TryErr(ctx, 3, func(ctx context.Context) error {
+				attempts++
+				return classify(exec.CommandContext(ctx, "sh", "-c", tc.command).Run())
+			})
+			assert.Equal(t, tc.wantAttempts, attempts)
+		})
+	}
+}
+
+func Test_exitCode(t *testing.T) {
+	cases := map[string]struct {
+		command string
+		want    int
+	}{
+		"Exit": {
+			command: "exit 3",
+			want:    3,
+		},
+		"Killed by signal": {
+			command: "kill -9 $$",
+			want:    128 + 9,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			err := exec.Command("sh", "-c", tc.command).Run()
+			var exitErr *exec.ExitError
+			require.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, tc.want, exitCode(exitErr))
+		})
+	}
+}
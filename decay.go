@@ -0,0 +1,94 @@
+package backoff
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Decaying is a stateful Intervals wrapper for long lived workers. Each call to
+// Next raises the level in the wrapped series, and the level decays back towards
+// the start of the series while no failures happen: the level is halved for
+// each HalfLife that passes without a call to Next. Unlike a hard reset, this
+// gradually recovers the aggressiveness of retries after intermittent failures.
+//
+// Since the state is kept across Try calls, the `i` and `last` arguments of Next
+// are ignored. A Decaying is safe for concurrent use.
+type Decaying struct {
+	intervals Intervals
+	halfLife  time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	level       int8
+	lastFailure time.Time
+}
+
+var _ Intervals = (*Decaying)(nil)
+
+// NewDecaying creates a new Decaying wrapper around iv. The level is halved for
+// each halfLife without failures, for example a halfLife of one minute halves
+// the level every healthy minute.
+func NewDecaying(iv Intervals, halfLife time.Duration) *Decaying {
+	return &Decaying{
+		intervals: iv,
+		halfLife:  halfLife,
+		now:       time.Now,
+	}
+}
+
+// Next decays the level for the time since the previous failure, returns the
+// interval for the current level and raises the level.
+func (d *Decaying) Next(i int8, last time.Duration) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	d.decay(now)
+	next := intervalAt(d.intervals, int(d.level))
+	if d.level < InfiniteTries {
+		d.level++
+	}
+	d.lastFailure = now
+	return next
+}
+
+// Level returns the current level in the wrapped series after decay.
+func (d *Decaying) Level() int8 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.decay(d.now())
+	return d.level
+}
+
+// Reset sets the level back to the start of the series.
+func (d *Decaying) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.level = 0
+}
+
+// String describes the series, for example "decaying exponential x2 from 500ms
+// capped 20s with half-life 1m0s".
+func (d *Decaying) String() string {
+	return fmt.Sprintf("decaying %s with half-life %s", Describe(d.intervals), d.halfLife)
+}
+
+// decay halves the level for each half life since the last failure. d.mu must
+// be held.
+func (d *Decaying) decay(now time.Time) {
+	if d.level == 0 || d.halfLife <= 0 {
+		return
+	}
+	halvings := now.Sub(d.lastFailure) / d.halfLife
+	if halvings <= 0 {
+		return
+	}
+	if halvings >= 8 {
+		// int8 levels are gone after 8 halvings
+		d.level = 0
+	} else {
+		d.level >>= uint(halvings)
+	}
+	// keep the remainder of the elapsed time towards the next halving
+	d.lastFailure = d.lastFailure.Add(halvings * d.halfLife)
+}
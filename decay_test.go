@@ -0,0 +1,59 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decaying(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	d := NewDecaying(DefaultBinaryExponential(), time.Minute)
+	d.now = func() time.Time { return now }
+
+	// failures raise the level: 0.5s, 1s, 2s, 4s
+	for _, want := range []time.Duration{
+		500 * time.Millisecond,
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+	} {
+		assert.Equal(t, want, d.Next(0, 0))
+	}
+	assert.Equal(t, int8(4), d.Level())
+
+	// half a minute is not enough to decay
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, int8(4), d.Level())
+
+	// one healthy minute halves the level
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, int8(2), d.Level())
+	assert.Equal(t, 2*time.Second, d.Next(0, 0))
+	assert.Equal(t, int8(3), d.Level())
+
+	// two healthy minutes halve it twice
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, int8(0), d.Level())
+	assert.Equal(t, 500*time.Millisecond, d.Next(0, 0))
+}
+
+func Test_Decaying_LongHealthyPeriodAndReset(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	d := NewDecaying(DefaultBinaryExponential(), time.Second)
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < InfiniteTries+10; i++ {
+		d.Next(0, 0)
+	}
+	assert.Equal(t, int8(InfiniteTries), d.Level())
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, int8(0), d.Level())
+
+	d.Next(0, 0)
+	d.Next(0, 0)
+	d.Reset()
+	assert.Equal(t, int8(0), d.Level())
+}
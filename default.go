@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	defaultMu     sync.RWMutex
+	defaultPolicy = Policy{
+		Name: "default",
+		Intervals: ExponentialJitter{
+			Exponential: DefaultBinaryExponential(),
+			JitterMax:   500 * time.Millisecond,
+			Rand:        rand.New(newLockedSource()),
+		},
+		Tries: 5,
+	}
+)
+
+// DefaultPolicy returns the policy set with SetDefault. Libraries should use it
+// when their users did not configure a policy, so applications can tune all of
+// them in one place, like http.DefaultClient.
+//
+// Unless changed, it makes 5 tries with DefaultBinaryExponential adjusted by a
+// random value between +/- 500ms.
+func DefaultPolicy() Policy {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultPolicy
+}
+
+// SetDefault replaces the policy returned by DefaultPolicy. It is meant to be
+// called by applications during start up.
+func SetDefault(p Policy) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultPolicy = p
+}
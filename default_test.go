@@ -0,0 +1,22 @@
+package backoff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_DefaultPolicy(t *testing.T) {
+	initial := backoff.DefaultPolicy()
+	assert.Equal(t, "default", initial.Name)
+	assert.Equal(t, int8(5), initial.Tries)
+	assert.Equal(t, "exponential x2 from 500ms capped 20s ±500ms jitter", backoff.Describe(initial.Intervals))
+	assert.Empty(t, backoff.Vet(initial))
+
+	backoff.SetDefault(backoff.PresetDatabase())
+	defer backoff.SetDefault(initial)
+
+	assert.Equal(t, "database", backoff.DefaultPolicy().Name)
+}
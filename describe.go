@@ -0,0 +1,13 @@
+package backoff
+
+import "fmt"
+
+// Describe returns a short description of the series of iv, like "exponential
+// x2 from 500ms capped 20s ±500ms jitter". It uses the String method if iv
+// implements fmt.Stringer and the type name otherwise.
+func Describe(iv Intervals) string {
+	if s, ok := iv.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", iv)
+}
@@ -0,0 +1,58 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+type customIntervals struct{}
+
+func (customIntervals) Next(i int8, last time.Duration) time.Duration {
+	return time.Second
+}
+
+func Test_Describe(t *testing.T) {
+	jitter, err := backoff.DefaultBinaryExponentialJitter()
+	assert.NoError(t, err)
+
+	cases := map[string]struct {
+		iv   backoff.Intervals
+		want string
+	}{
+		"Exponential": {
+			iv:   backoff.DefaultBinaryExponential(),
+			want: "exponential x2 from 500ms capped 20s",
+		},
+		"ExponentialJitter": {
+			iv:   jitter,
+			want: "exponential x2 from 500ms capped 20s ±500ms jitter",
+		},
+		"Empirical": {
+			iv:   backoff.NewEmpirical(backoff.DefaultBinaryExponential(), 0.5, 0.9, 0.99),
+			want: "empirical at p50, p90, p99 with fallback exponential x2 from 500ms capped 20s",
+		},
+		"Decaying": {
+			iv:   backoff.NewDecaying(backoff.DefaultBinaryExponential(), time.Minute),
+			want: "decaying exponential x2 from 500ms capped 20s with half-life 1m0s",
+		},
+		"RampUp": {
+			iv:   backoff.NewRampUp(backoff.DefaultBinaryExponential(), 4),
+			want: "ramp up 4s, 2s, 1s, 500ms",
+		},
+		"Without Stringer": {
+			iv:   customIntervals{},
+			want: "backoff_test.customIntervals",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.want, backoff.Describe(tc.iv))
+		})
+	}
+}
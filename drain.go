@@ -0,0 +1,65 @@
+package backoff
+
+import "sync"
+
+// Draining is returned by Try and TryErr when an attempt failed while the
+// Backoff was draining, see SetDraining
+const Draining = Error("backoff draining")
+
+// SetDraining turns draining on or off. While draining, a Try call whose
+// attempt failed returns Draining instead of pausing for a retry, and calls
+// in a backoff pause wake up and return Draining. The first attempt of a call
+// is still made. This supports load balancer drain workflows where long
+// backoff pauses would delay the termination of the process.
+//
+// The Backoff copies made for per call options share the flag.
+func (b *Backoff) SetDraining(draining bool) {
+	b.drain.set(draining)
+}
+
+// drain is the draining flag of a Backoff. The channel is closed while
+// draining so pauses can wait for it.
+type drain struct {
+	mu sync.Mutex
+	on bool
+	ch chan struct{}
+}
+
+func newDrain() *drain {
+	return &drain{ch: make(chan struct{})}
+}
+
+func (d *drain) set(on bool) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case on && !d.on:
+		close(d.ch)
+	case !on && d.on:
+		d.ch = make(chan struct{})
+	}
+	d.on = on
+}
+
+// started returns a channel closed while draining, nil for a nil drain
+func (d *drain) started() <-chan struct{} {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.ch
+}
+
+// active reports if draining is on. It is safe to call on a nil drain.
+func (d *drain) active() bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.on
+}
@@ -0,0 +1,52 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SetDraining(t *testing.T) {
+	bo := NewBackoff(minuteInterval)
+	bo.SetDraining(true)
+
+	calls := 0
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return false
+	})
+	assert.Equal(t, Draining, err)
+	assert.Equal(t, 1, calls)
+
+	bo.SetDraining(false)
+	calls = 0
+	err = bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return false
+	}, UseIntervals(Immediate(0).Intervals))
+	assert.Equal(t, AllTriesFailed, err)
+	assert.Equal(t, 3, calls)
+}
+
+func Test_SetDraining_WakesPauses(t *testing.T) {
+	bo := NewBackoff(minuteInterval)
+	attempted := make(chan struct{}, 1)
+	result := make(chan error)
+	go func() {
+		result <- bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+			attempted <- struct{}{}
+			return false
+		})
+	}()
+	<-attempted
+
+	bo.SetDraining(true)
+	select {
+	case err := <-result:
+		assert.Equal(t, Draining, err)
+	case <-time.After(time.Second):
+		t.Fatal("Try did not return after SetDraining")
+	}
+}
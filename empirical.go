@@ -0,0 +1,102 @@
+package backoff
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// empiricalMaxSamples bounds the number of samples kept by Empirical. Older
+// samples are replaced by newer ones.
+const empiricalMaxSamples = 1024
+
+// Empirical implements a data driven Intervals. It is fed observed times to
+// recovery of a dependency (for example outage durations from telemetry) with
+// Observe, and schedules retries at the chosen percentiles of the observed
+// distribution. With percentiles 0.5, 0.9 and 0.99 the second attempt happens
+// when half of the observed outages had recovered, the third when 90% had
+// recovered, and so on.
+//
+// Until a sample is observed, and for iterations beyond the chosen percentiles,
+// the fallback Intervals is used. An Empirical is safe for concurrent use.
+type Empirical struct {
+	fallback    Intervals
+	percentiles []float64
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int // ring buffer position once samples is full
+}
+
+var _ Intervals = (*Empirical)(nil)
+
+// NewEmpirical creates a new Empirical. Percentiles should be increasing values
+// in the range (0, 1].
+func NewEmpirical(fallback Intervals, percentiles ...float64) *Empirical {
+	return &Empirical{
+		fallback:    fallback,
+		percentiles: percentiles,
+	}
+}
+
+// Observe records a time to recovery sample.
+func (e *Empirical) Observe(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.samples) < empiricalMaxSamples {
+		e.samples = append(e.samples, d)
+		return
+	}
+	e.samples[e.next] = d
+	e.next = (e.next + 1) % empiricalMaxSamples
+}
+
+// Next returns the time between the percentile of iteration `i` and the
+// percentile of the previous iteration so that attempts happen at the chosen
+// percentiles of the time to recovery.
+func (e *Empirical) Next(i int8, last time.Duration) time.Duration {
+	e.mu.Lock()
+	if len(e.samples) == 0 || int(i) >= len(e.percentiles) {
+		e.mu.Unlock()
+		return e.fallback.Next(i, last)
+	}
+	sorted := make([]time.Duration, len(e.samples))
+	copy(sorted, e.samples)
+	e.mu.Unlock()
+
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+	at := percentile(sorted, e.percentiles[i])
+	if i > 0 {
+		at -= percentile(sorted, e.percentiles[i-1])
+	}
+	if at < 0 {
+		return 0
+	}
+	return at
+}
+
+// String describes the series, for example "empirical at p50, p90 with
+// fallback exponential x2 from 500ms capped 20s".
+func (e *Empirical) String() string {
+	ps := make([]string, len(e.percentiles))
+	for i, p := range e.percentiles {
+		ps[i] = "p" + strconv.FormatFloat(p*100, 'f', -1, 64)
+	}
+	return fmt.Sprintf("empirical at %s with fallback %s", strings.Join(ps, ", "), Describe(e.fallback))
+}
+
+// percentile returns the nearest rank percentile p of the sorted samples
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
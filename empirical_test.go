@@ -0,0 +1,60 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Empirical(t *testing.T) {
+	e := NewEmpirical(DefaultBinaryExponential(), 0.5, 0.9, 1)
+
+	// no samples uses the fallback
+	assert.Equal(t, 500*time.Millisecond, e.Next(0, 0))
+
+	for i := 1; i <= 10; i++ {
+		e.Observe(time.Duration(i) * time.Second)
+	}
+
+	cases := map[string]struct {
+		i    int8
+		want time.Duration
+	}{
+		"p50": {
+			i:    0,
+			want: 5 * time.Second,
+		},
+		"p90 after p50": {
+			i:    1,
+			want: 4 * time.Second,
+		},
+		"p100 after p90": {
+			i:    2,
+			want: 1 * time.Second,
+		},
+		"beyond percentiles uses the fallback": {
+			i:    3,
+			want: 4 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.want, e.Next(tc.i, 0))
+		})
+	}
+}
+
+func Test_Empirical_BoundedSamples(t *testing.T) {
+	e := NewEmpirical(DefaultBinaryExponential(), 1)
+	for i := 0; i < empiricalMaxSamples; i++ {
+		e.Observe(time.Hour)
+	}
+	for i := 0; i < empiricalMaxSamples; i++ {
+		e.Observe(time.Second)
+	}
+	assert.Len(t, e.samples, empiricalMaxSamples)
+	assert.Equal(t, time.Second, e.Next(0, 0))
+}
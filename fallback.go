@@ -0,0 +1,71 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+)
+
+// FallbackBudget decides how tries are shared between the targets of
+// TryWithFallback.
+type FallbackBudget int
+
+const (
+	// FreshBudget gives every target the full number of tries
+	FreshBudget FallbackBudget = iota
+	// SharedBudget shares the number of tries between all targets
+	SharedBudget
+)
+
+// TryWithFallback calls Try on the primary Completable, and once it gives up,
+// on each fallback in order until one succeeds. This is useful for ordered
+// failover, like a multi-region client. It returns the index of the target that
+// succeeded, 0 for the primary and 1 for the first fallback, or -1 and the
+// error of the last target if all targets failed. If the context is done, no
+// further targets are tried.
+func (b *Backoff) TryWithFallback(ctx context.Context, tries int8, budget FallbackBudget, primary Completable, fallbacks ...Completable) (int, error) {
+	targets := make([]Operation, 0, len(fallbacks)+1)
+	for _, fn := range append([]Completable{primary}, fallbacks...) {
+		targets = append(targets, fn.operation())
+	}
+	return b.fallback(ctx, tries, budget, targets, func(ctx context.Context, tries int8, fn Operation) error {
+		err, _, h := b.run(ctx, tries, fn, 0, 0)
+		if err != nil {
+			b.giveUp(h, err)
+		}
+		return err
+	})
+}
+
+// TryErrWithFallback is like TryWithFallback but calls TryErr with Operations.
+func (b *Backoff) TryErrWithFallback(ctx context.Context, tries int8, budget FallbackBudget, primary Operation, fallbacks ...Operation) (int, error) {
+	targets := append([]Operation{primary}, fallbacks...)
+	return b.fallback(ctx, tries, budget, targets, func(ctx context.Context, tries int8, fn Operation) error {
+		return b.TryErr(ctx, tries, fn)
+	})
+}
+
+func (b *Backoff) fallback(ctx context.Context, tries int8, budget FallbackBudget, targets []Operation, try func(context.Context, int8, Operation) error) (int, error) {
+	remaining := tries
+	var err error
+	for n, target := range targets {
+		if budget == SharedBudget && remaining <= 0 {
+			break
+		}
+		attempts := 0
+		counted := func(ctx context.Context) error {
+			attempts++
+			return target(ctx)
+		}
+		err = try(ctx, remaining, counted)
+		if err == nil {
+			return n, nil
+		}
+		if errors.Is(err, BackoffContextTimeoutExceeded) {
+			return -1, err
+		}
+		if budget == SharedBudget && remaining != InfiniteTries {
+			remaining -= int8(attempts)
+		}
+	}
+	return -1, err
+}
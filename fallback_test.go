@@ -0,0 +1,95 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_TryWithFallback(t *testing.T) {
+	cases := map[string]struct {
+		budget       backoff.FallbackBudget
+		succeedAt    int // target index that succeeds, -1 for none
+		wantTarget   int
+		wantErr      error
+		wantAttempts []int
+	}{
+		"Primary succeeds": {
+			budget:       backoff.FreshBudget,
+			succeedAt:    0,
+			wantTarget:   0,
+			wantAttempts: []int{1, 0, 0},
+		},
+		"Second fallback succeeds with fresh budget": {
+			budget:       backoff.FreshBudget,
+			succeedAt:    2,
+			wantTarget:   2,
+			wantAttempts: []int{3, 3, 1},
+		},
+		"All fail with fresh budget": {
+			budget:       backoff.FreshBudget,
+			succeedAt:    -1,
+			wantTarget:   -1,
+			wantErr:      backoff.AllTriesFailed,
+			wantAttempts: []int{3, 3, 3},
+		},
+		"Shared budget runs out": {
+			budget:       backoff.SharedBudget,
+			succeedAt:    2,
+			wantTarget:   -1,
+			wantErr:      backoff.AllTriesFailed,
+			wantAttempts: []int{3, 0, 0},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			attempts := make([]int, 3)
+			target := func(n int) backoff.Completable {
+				return func(ctx context.Context) bool {
+					attempts[n]++
+					return n == tc.succeedAt
+				}
+			}
+			bo := backoff.NewBackoff(shortInterval)
+			got, err := bo.TryWithFallback(ctx, 3, tc.budget, target(0), target(1), target(2))
+
+			assert.Equal(t, tc.wantTarget, got)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantAttempts, attempts)
+		})
+	}
+}
+
+func Test_TryErrWithFallback_SharedBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	primaryErr := errors.New("region down")
+	bo := backoff.NewBackoff(shortInterval)
+
+	attempts := 0
+	got, err := bo.TryErrWithFallback(ctx, 4, backoff.SharedBudget,
+		func(ctx context.Context) error {
+			return backoff.Permanent(primaryErr)
+		},
+		func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("fail")
+			}
+			return nil
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got)
+	assert.Equal(t, 3, attempts)
+}
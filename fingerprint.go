@@ -0,0 +1,83 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// ErrorGroup counts the attempt errors that share a fingerprint.
+type ErrorGroup struct {
+	// Fingerprint identifies the group. It is a hash of the message of the
+	// innermost error, so wrapped variants of the same error share a group.
+	Fingerprint string
+	// Message is the message of the innermost error
+	Message string
+	// Err is the first error of the group
+	Err error
+	// Count is the number of errors in the group
+	Count int
+}
+
+// ErrorGroups are attempt errors grouped by fingerprint in the order their
+// first error occurred.
+type ErrorGroups []ErrorGroup
+
+// String summarizes the groups, for example "5x connection refused, 2x
+// timeout".
+func (g ErrorGroups) String() string {
+	parts := make([]string, len(g))
+	for i, group := range g {
+		parts[i] = fmt.Sprintf("%dx %s", group.Count, group.Message)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GroupErrors groups errs by fingerprint. nil errors are skipped.
+func GroupErrors(errs []error) ErrorGroups {
+	var groups ErrorGroups
+	index := make(map[string]int)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		message := rootCause(err).Error()
+		fp := fingerprint(message)
+		if i, ok := index[fp]; ok {
+			groups[i].Count++
+			continue
+		}
+		index[fp] = len(groups)
+		groups = append(groups, ErrorGroup{
+			Fingerprint: fp,
+			Message:     message,
+			Err:         err,
+			Count:       1,
+		})
+	}
+	return groups
+}
+
+// ErrorGroups groups the attempt errors by fingerprint.
+func (g GiveUpInfo) ErrorGroups() ErrorGroups {
+	return GroupErrors(g.Errors)
+}
+
+// rootCause follows the single error Unwrap chain of err to the innermost error
+func rootCause(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+func fingerprint(message string) string {
+	h := fnv.New64a()
+	h.Write([]byte(message))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
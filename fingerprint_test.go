@@ -0,0 +1,67 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_GroupErrors(t *testing.T) {
+	refused := errors.New("connection refused")
+	timeout := errors.New("timeout")
+
+	cases := map[string]struct {
+		errs       []error
+		wantCounts []int
+		want       string
+	}{
+		"No errors": {},
+		"Single group": {
+			errs:       []error{refused, refused},
+			wantCounts: []int{2},
+			want:       "2x connection refused",
+		},
+		"Wrapped errors share a group": {
+			errs: []error{
+				fmt.Errorf("dial 10.0.0.1: %w", refused),
+				timeout,
+				fmt.Errorf("dial 10.0.0.2: %w", refused),
+				nil,
+				refused,
+			},
+			wantCounts: []int{3, 1},
+			want:       "3x connection refused, 1x timeout",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			groups := backoff.GroupErrors(tc.errs)
+			require.Len(t, groups, len(tc.wantCounts))
+			for i, count := range tc.wantCounts {
+				assert.Equal(t, count, groups[i].Count)
+				assert.NotEmpty(t, groups[i].Fingerprint)
+			}
+			assert.Equal(t, tc.want, groups.String())
+		})
+	}
+}
+
+func Test_GiveUpInfo_ErrorGroups(t *testing.T) {
+	var info backoff.GiveUpInfo
+	bo := backoff.NewBackoff(shortInterval, backoff.WithOnGiveUp(func(i backoff.GiveUpInfo) {
+		info = i
+	}))
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		return false
+	})
+	assert.Equal(t, backoff.AllTriesFailed, err)
+	assert.Equal(t, "3x attempt failed", info.ErrorGroups().String())
+}
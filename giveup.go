@@ -0,0 +1,87 @@
+package backoff
+
+import "time"
+
+// GiveUpInfo describes a Try call that gave up because all tries failed or the
+// context was done. It is passed to the function provided with WithOnGiveUp.
+type GiveUpInfo struct {
+	// Err is the error returned by Try or TryErr
+	Err error
+	// Errors holds the error of each attempt in order. A Completable that
+	// returned false is recorded as AttemptFailed.
+	Errors []error
+	// Durations holds how long each attempt took
+	Durations []time.Duration
+	// Waits holds the backoff pause computed after each failed attempt. The
+	// last wait may have been interrupted by the context.
+	Waits []time.Duration
+	// Intervals is the series used to compute Waits
+	Intervals Intervals
+}
+
+// Attempts is the number of attempts made before giving up.
+func (g GiveUpInfo) Attempts() int {
+	return len(g.Errors)
+}
+
+// WithOnGiveUp calls fn exactly once when Try gives up, either because all
+// tries failed or because the context was done. The GiveUpInfo contains the
+// full history of the Try call which is useful to emit a single report or
+// alert instead of logging every attempt.
+func WithOnGiveUp(fn func(GiveUpInfo)) Options {
+	return func(bo *Backoff) {
+		bo.onGiveUp = fn
+	}
+}
+
+// history records the attempts of a single Try call. The attempt errors,
+// durations and waits are only kept if something consumes them. This avoids
+// unbounded growth for InfiniteTries when recording is not needed.
+type history struct {
+	keep      bool
+	start     time.Time
+	attempts  int
+	pauses    int
+	waited    time.Duration
+	errs      []error
+	durations []time.Duration
+	waits     []time.Duration
+}
+
+func (b *Backoff) newHistory() *history {
+	return &history{
+		keep:  b.onGiveUp != nil || b.collectErrors,
+		start: time.Now(),
+	}
+}
+
+func (h *history) attempt(err error, d time.Duration) {
+	h.attempts++
+	if !h.keep {
+		return
+	}
+	h.errs = append(h.errs, err)
+	h.durations = append(h.durations, d)
+}
+
+func (h *history) wait(d time.Duration) {
+	h.pauses++
+	h.waited += d
+	if !h.keep {
+		return
+	}
+	h.waits = append(h.waits, d)
+}
+
+// giveUp reports the history to the give up callback
+func (b *Backoff) giveUp(h *history, err error) {
+	if b.onGiveUp != nil {
+		b.onGiveUp(GiveUpInfo{
+			Err:       err,
+			Errors:    h.errs,
+			Durations: h.durations,
+			Waits:     h.waits,
+			Intervals: b.intervals,
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff/test/try"
+)
+
+func Test_WithOnGiveUp(t *testing.T) {
+	shortInterval := Exponential{
+		Base:    2 * time.Millisecond,
+		Unit:    time.Millisecond,
+		Initial: 1 * time.Millisecond,
+		Max:     20 * time.Millisecond,
+	}
+
+	cases := map[string]struct {
+		trueAfterN int
+		tries      int8
+		timeout    time.Duration
+		wantCalls  int
+		wantErr    error
+		wantErrors []error
+		wantWaits  []time.Duration
+	}{
+		"Not called on success": {
+			trueAfterN: 1,
+			tries:      3,
+			timeout:    time.Second,
+			wantCalls:  0,
+			wantErr:    nil,
+		},
+		"Called once after all tries failed": {
+			trueAfterN: 3,
+			tries:      3,
+			timeout:    time.Second,
+			wantCalls:  1,
+			wantErr:    AllTriesFailed,
+			wantErrors: []error{AttemptFailed, AttemptFailed, AttemptFailed},
+			wantWaits: []time.Duration{
+				1 * time.Millisecond,
+				2 * time.Millisecond,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var infos []GiveUpInfo
+			_, tryFn := try.FnLogger(0, tc.trueAfterN)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			bo := NewBackoff(shortInterval, WithOnGiveUp(func(info GiveUpInfo) {
+				infos = append(infos, info)
+			}))
+			err := bo.Try(ctx, tc.tries, tryFn)
+
+			assert.Equal(t, tc.wantErr, err)
+			require.Len(t, infos, tc.wantCalls)
+			if tc.wantCalls == 0 {
+				return
+			}
+			info := infos[0]
+			assert.Equal(t, tc.wantErr, info.Err)
+			assert.Equal(t, tc.wantErrors, info.Errors)
+			assert.Equal(t, len(tc.wantErrors), info.Attempts())
+			assert.Len(t, info.Durations, len(tc.wantErrors))
+			assert.Equal(t, tc.wantWaits, info.Waits)
+			assert.Equal(t, shortInterval, info.Intervals)
+		})
+	}
+}
+
+func Test_WithOnGiveUp_ContextTimeout(t *testing.T) {
+	var infos []GiveUpInfo
+	interval := Exponential{
+		Base:    time.Second,
+		Unit:    time.Second,
+		Initial: time.Second,
+		Max:     time.Second,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	bo := NewBackoff(interval, WithOnGiveUp(func(info GiveUpInfo) {
+		infos = append(infos, info)
+	}))
+	err := bo.Try(ctx, InfiniteTries, func(ctx context.Context) bool {
+		return false
+	})
+
+	assert.Equal(t, BackoffContextTimeoutExceeded, err)
+	require.Len(t, infos, 1)
+	assert.Equal(t, BackoffContextTimeoutExceeded, infos[0].Err)
+	assert.Equal(t, []error{AttemptFailed}, infos[0].Errors)
+	assert.Equal(t, []time.Duration{time.Second}, infos[0].Waits)
+}
@@ -0,0 +1,94 @@
+package backoff
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// HeatMap is a per-key time-bucketed matrix of attempts and failures, for
+// example to render which dependencies or tenants drive the retry load.
+// Attempts[k][n] is the number of attempts of Keys[k] that started in the
+// bucket starting at Start + n*Bucket.
+type HeatMap struct {
+	Start    time.Time
+	Bucket   time.Duration
+	Keys     []string
+	Attempts [][]int
+	Failures [][]int
+}
+
+// NewHeatMap builds a HeatMap from the records of a Recorder per key, with
+// buckets of size bucket aligned to multiples of bucket. Keys are sorted.
+func NewHeatMap(recorders map[string]*Recorder, bucket time.Duration) HeatMap {
+	if bucket <= 0 {
+		bucket = time.Second
+	}
+	h := HeatMap{Bucket: bucket}
+	snapshots := make(map[string][]Record, len(recorders))
+	var first, last time.Time
+	for key, r := range recorders {
+		h.Keys = append(h.Keys, key)
+		records := r.Snapshot()
+		snapshots[key] = records
+		for _, rec := range records {
+			if first.IsZero() || rec.Start.Before(first) {
+				first = rec.Start
+			}
+			if rec.Start.After(last) {
+				last = rec.Start
+			}
+		}
+	}
+	sort.Strings(h.Keys)
+	if first.IsZero() {
+		h.Attempts = make([][]int, len(h.Keys))
+		h.Failures = make([][]int, len(h.Keys))
+		return h
+	}
+	h.Start = first.Truncate(bucket)
+	n := int(last.Sub(h.Start)/bucket) + 1
+	for _, key := range h.Keys {
+		attempts, failures := make([]int, n), make([]int, n)
+		for _, rec := range snapshots[key] {
+			at := int(rec.Start.Sub(h.Start) / bucket)
+			attempts[at]++
+			if rec.Err != nil {
+				failures[at]++
+			}
+		}
+		h.Attempts = append(h.Attempts, attempts)
+		h.Failures = append(h.Failures, failures)
+	}
+	return h
+}
+
+type heatMapJSON struct {
+	Start         time.Time `json:"start"`
+	Bucket        string    `json:"bucket"`
+	BucketSeconds float64   `json:"bucket_seconds"`
+	Keys          []string  `json:"keys"`
+	Attempts      [][]int   `json:"attempts"`
+	Failures      [][]int   `json:"failures"`
+}
+
+// MarshalJSON encodes the HeatMap with the bucket size as a string and in
+// seconds, like DumpSchedule.
+func (h HeatMap) MarshalJSON() ([]byte, error) {
+	return json.Marshal(heatMapJSON{
+		Start:         h.Start,
+		Bucket:        h.Bucket.String(),
+		BucketSeconds: h.Bucket.Seconds(),
+		Keys:          h.Keys,
+		Attempts:      h.Attempts,
+		Failures:      h.Failures,
+	})
+}
+
+// WriteJSON writes the HeatMap to w as indented JSON.
+func (h HeatMap) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
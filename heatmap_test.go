@@ -0,0 +1,44 @@
+package backoff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewHeatMap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	errFail := errors.New("fail")
+	db, api := NewRecorder(10), NewRecorder(10)
+	db.record(start.Add(100*time.Millisecond), 1, errFail, 0, 0)
+	db.record(start.Add(600*time.Millisecond), 2, errFail, 0, 0)
+	db.record(start.Add(2500*time.Millisecond), 3, nil, 0, 0)
+	api.record(start.Add(1200*time.Millisecond), 1, nil, 0, 0)
+
+	h := NewHeatMap(map[string]*Recorder{"db": db, "api": api, "idle": NewRecorder(1)}, time.Second)
+	assert.Equal(t, start, h.Start)
+	assert.Equal(t, []string{"api", "db", "idle"}, h.Keys)
+	assert.Equal(t, [][]int{{0, 1, 0}, {2, 0, 1}, {0, 0, 0}}, h.Attempts)
+	assert.Equal(t, [][]int{{0, 0, 0}, {2, 0, 0}, {0, 0, 0}}, h.Failures)
+
+	var buf bytes.Buffer
+	require.NoError(t, h.WriteJSON(&buf))
+	assert.JSONEq(t, `{
+		"start": "2024-01-01T12:00:00Z",
+		"bucket": "1s",
+		"bucket_seconds": 1,
+		"keys": ["api", "db", "idle"],
+		"attempts": [[0, 1, 0], [2, 0, 1], [0, 0, 0]],
+		"failures": [[0, 0, 0], [2, 0, 0], [0, 0, 0]]
+	}`, buf.String())
+}
+
+func Test_NewHeatMap_Empty(t *testing.T) {
+	h := NewHeatMap(map[string]*Recorder{"db": NewRecorder(1)}, time.Minute)
+	assert.True(t, h.Start.IsZero())
+	assert.Equal(t, [][]int{nil}, h.Attempts)
+}
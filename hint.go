@@ -0,0 +1,66 @@
+package backoff
+
+import (
+	"errors"
+	"time"
+)
+
+// HintProvider extracts a server provided retry delay from the error of a
+// failed attempt, for example a protocol specific "retry after" field. If it
+// returns true, the delay is used instead of the Intervals value.
+type HintProvider interface {
+	RetryAfterHint(err error) (time.Duration, bool)
+}
+
+// HintProviderFunc adapts a function to a HintProvider.
+type HintProviderFunc func(err error) (time.Duration, bool)
+
+// RetryAfterHint calls fn.
+func (fn HintProviderFunc) RetryAfterHint(err error) (time.Duration, bool) {
+	return fn(err)
+}
+
+// WithHintProvider consults hp after each failed attempt. Errors wrapped with
+// RetryAfter are always honored even without a HintProvider.
+func WithHintProvider(hp HintProvider) Options {
+	return func(bo *Backoff) {
+		bo.hints = hp
+	}
+}
+
+// retryAfterError carries a retry delay
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (r *retryAfterError) Error() string {
+	return r.err.Error()
+}
+
+func (r *retryAfterError) Unwrap() error {
+	return r.err
+}
+
+// RetryAfter wraps err with a delay that the retry loop uses for the next pause
+// instead of the Intervals value. RetryAfter returns nil if err is nil.
+func RetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAfterError{err: err, delay: delay}
+}
+
+// hint returns the pause to use after an attempt that failed with err
+func (b *Backoff) hint(err error, wait time.Duration) time.Duration {
+	var ra *retryAfterError
+	if errors.As(err, &ra) {
+		return ra.delay
+	}
+	if b.hints != nil {
+		if d, ok := b.hints.RetryAfterHint(err); ok {
+			return d
+		}
+	}
+	return wait
+}
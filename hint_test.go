@@ -0,0 +1,80 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+type throttled struct {
+	after time.Duration
+}
+
+func (t throttled) Error() string {
+	return "throttled"
+}
+
+func Test_Hints(t *testing.T) {
+	provider := backoff.HintProviderFunc(func(err error) (time.Duration, bool) {
+		var th throttled
+		if errors.As(err, &th) {
+			return th.after, true
+		}
+		return 0, false
+	})
+
+	cases := map[string]struct {
+		options   []backoff.Options
+		errs      []error
+		wantWaits []time.Duration
+	}{
+		"RetryAfter": {
+			errs: []error{
+				backoff.RetryAfter(errors.New("busy"), 5*time.Millisecond),
+				errors.New("other"),
+			},
+			wantWaits: []time.Duration{5 * time.Millisecond, 2 * time.Millisecond},
+		},
+		"HintProvider": {
+			options: []backoff.Options{backoff.WithHintProvider(provider)},
+			errs: []error{
+				errors.New("other"),
+				throttled{after: 7 * time.Millisecond},
+			},
+			wantWaits: []time.Duration{1 * time.Millisecond, 7 * time.Millisecond},
+		},
+		"No hint without provider": {
+			errs: []error{
+				throttled{after: 7 * time.Millisecond},
+			},
+			wantWaits: []time.Duration{1 * time.Millisecond},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			rec := backoff.NewRecorder(10)
+			options := append([]backoff.Options{backoff.WithRecorder(rec)}, tc.options...)
+			bo := backoff.NewBackoff(shortInterval, options...)
+			err := bo.TryErr(ctx, 5, failWith(tc.errs...))
+			require.NoError(t, err)
+
+			var waits []time.Duration
+			for _, r := range rec.Snapshot() {
+				if r.Wait > 0 {
+					waits = append(waits, r.Wait)
+				}
+			}
+			assert.Equal(t, tc.wantWaits, waits)
+		})
+	}
+}
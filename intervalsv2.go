@@ -0,0 +1,121 @@
+package backoff
+
+import "time"
+
+// IntervalsV2 is the successor of Intervals. `attempt` is the position in the
+// series starting from 0 and is not limited to int8. `last` is the previous
+// backoff pause, zero for the first. `elapsed` is the time since the Try call
+// started, so strategies can for example slow down after a while.
+//
+// New strategies should implement IntervalsV2 and use FromIntervalsV2 to pass
+// them where an Intervals is expected.
+type IntervalsV2 interface {
+	Next(attempt int, last time.Duration, elapsed time.Duration) time.Duration
+}
+
+// FromIntervalsV2 adapts an IntervalsV2 to Intervals. A Backoff created with
+// the adapter calls iv with the real position and elapsed time. Other users of
+// Intervals, like Schedule, pass an elapsed time of zero.
+func FromIntervalsV2(iv IntervalsV2) Intervals {
+	if v1, ok := iv.(intervalsV1); ok {
+		return v1.iv
+	}
+	return intervalsV2{iv: iv}
+}
+
+// ToIntervalsV2 adapts an Intervals to IntervalsV2. Positions beyond
+// InfiniteTries use InfiniteTries and the elapsed time is ignored.
+func ToIntervalsV2(iv Intervals) IntervalsV2 {
+	if v2, ok := iv.(intervalsV2); ok {
+		return v2.iv
+	}
+	return intervalsV1{iv: iv}
+}
+
+// NewBackoffV2 is like NewBackoff but takes an IntervalsV2.
+func NewBackoffV2(iv IntervalsV2, options ...Options) *Backoff {
+	return NewBackoff(FromIntervalsV2(iv), options...)
+}
+
+// intervalsV2 is an IntervalsV2 adapted to Intervals
+type intervalsV2 struct {
+	iv IntervalsV2
+}
+
+func (a intervalsV2) Next(i int8, last time.Duration) time.Duration {
+	return a.iv.Next(int(i), last, 0)
+}
+
+// intervalsV1 is an Intervals adapted to IntervalsV2
+type intervalsV1 struct {
+	iv Intervals
+}
+
+func (a intervalsV1) Next(attempt int, last time.Duration, elapsed time.Duration) time.Duration {
+	if attempt > InfiniteTries {
+		attempt = InfiniteTries
+	}
+	return a.iv.Next(int8(attempt), last)
+}
+
+// v2 returns the IntervalsV2 behind iv if it is an adapted IntervalsV2
+func v2(iv Intervals) IntervalsV2 {
+	if a, ok := iv.(intervalsV2); ok {
+		return a.iv
+	}
+	return nil
+}
+
+// v2Step returns the position passed to an IntervalsV2. It follows idx when
+// the index is capped or wrapped, which are defined on int8 positions.
+func (b *Backoff) v2Step(step int, idx int8) int {
+	if b.wrapIndex || b.maxIndex != InfiniteTries {
+		return int(idx)
+	}
+	return step
+}
+
+// Budget is a snapshot of what is left of a Try call when the next backoff
+// pause is computed.
+type Budget struct {
+	// Attempt is the position in the series, like in IntervalsV2
+	Attempt int
+	// Elapsed is the time since the Try call started
+	Elapsed time.Duration
+	// RemainingTries is the number of tries left, -1 for InfiniteTries
+	RemainingTries int
+	// Deadline is the deadline of the context, zero if it has none
+	Deadline time.Time
+	// RemainingTime is the time left until Deadline, zero if there is no
+	// deadline or it passed
+	RemainingTime time.Duration
+}
+
+// HasDeadline reports if the context of the Try call has a deadline.
+func (b Budget) HasDeadline() bool {
+	return !b.Deadline.IsZero()
+}
+
+// BudgetIntervals is an IntervalsV2 that also wants the Budget of the Try
+// call, for example to spread the remaining tries over the remaining time.
+// A Backoff created with FromIntervalsV2 calls NextBudget instead of Next.
+type BudgetIntervals interface {
+	IntervalsV2
+	NextBudget(last time.Duration, budget Budget) time.Duration
+}
+
+// budget returns the Budget of the attempt
+func (a *Attempt) budget() Budget {
+	b := Budget{
+		Attempt:        a.step,
+		Elapsed:        time.Since(a.start),
+		RemainingTries: a.Remaining,
+		Deadline:       a.deadline,
+	}
+	if b.HasDeadline() {
+		if left := time.Until(b.Deadline); left > 0 {
+			b.RemainingTime = left
+		}
+	}
+	return b
+}
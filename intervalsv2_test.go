@@ -0,0 +1,124 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+// recordingV2 records the arguments of Next and never waits
+type recordingV2 struct {
+	attempts []int
+	elapsed  []time.Duration
+}
+
+func (r *recordingV2) Next(attempt int, last time.Duration, elapsed time.Duration) time.Duration {
+	r.attempts = append(r.attempts, attempt)
+	r.elapsed = append(r.elapsed, elapsed)
+	return 0
+}
+
+func Test_NewBackoffV2(t *testing.T) {
+	iv := &recordingV2{}
+	bo := backoff.NewBackoffV2(iv)
+
+	calls := 0
+	err := bo.Try(context.Background(), backoff.InfiniteTries, func(ctx context.Context) bool {
+		calls++
+		return calls == 200
+	})
+	require.NoError(t, err)
+
+	// the position is not capped at InfiniteTries
+	require.Len(t, iv.attempts, 199)
+	for i, attempt := range iv.attempts {
+		assert.Equal(t, i, attempt)
+	}
+	for i := 1; i < len(iv.elapsed); i++ {
+		assert.GreaterOrEqual(t, iv.elapsed[i], iv.elapsed[i-1])
+	}
+}
+
+func Test_IntervalsV2Adapters(t *testing.T) {
+	exp := backoff.DefaultBinaryExponential()
+
+	v2 := backoff.ToIntervalsV2(exp)
+	assert.Equal(t, 2*time.Second, v2.Next(2, time.Second, time.Minute))
+	assert.Equal(t, 20*time.Second, v2.Next(1000, 0, 0))
+	assert.Equal(t, exp, backoff.FromIntervalsV2(v2))
+
+	rec := &recordingV2{}
+	v1 := backoff.FromIntervalsV2(rec)
+	assert.Equal(t, time.Duration(0), v1.Next(3, time.Second))
+	assert.Equal(t, []int{3}, rec.attempts)
+	assert.Equal(t, backoff.IntervalsV2(rec), backoff.ToIntervalsV2(v1))
+}
+
+// recordingBudget records the Budget of NextBudget and never waits
+type recordingBudget struct {
+	recordingV2
+	budgets []backoff.Budget
+}
+
+func (r *recordingBudget) NextBudget(last time.Duration, budget backoff.Budget) time.Duration {
+	r.budgets = append(r.budgets, budget)
+	return 0
+}
+
+func Test_BudgetIntervals(t *testing.T) {
+	cases := map[string]struct {
+		timeout       time.Duration
+		tries         int8
+		wantRemaining []int
+	}{
+		"Without deadline": {
+			tries:         4,
+			wantRemaining: []int{3, 2, 1},
+		},
+		"With deadline": {
+			timeout:       time.Minute,
+			tries:         3,
+			wantRemaining: []int{2, 1},
+		},
+		"Infinite tries": {
+			tries:         backoff.InfiniteTries,
+			wantRemaining: []int{-1, -1, -1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			iv := &recordingBudget{}
+			calls := 0
+			_ = backoff.NewBackoffV2(iv).Try(ctx, tc.tries, func(ctx context.Context) bool {
+				calls++
+				return calls == 4
+			})
+
+			assert.Empty(t, iv.attempts, "Next is not called")
+			require.Len(t, iv.budgets, len(tc.wantRemaining))
+			for i, b := range iv.budgets {
+				assert.Equal(t, i, b.Attempt)
+				assert.Equal(t, tc.wantRemaining[i], b.RemainingTries)
+				assert.Equal(t, tc.timeout > 0, b.HasDeadline())
+				if tc.timeout > 0 {
+					assert.True(t, b.RemainingTime > 0 && b.RemainingTime <= tc.timeout)
+				} else {
+					assert.Zero(t, b.RemainingTime)
+				}
+			}
+		})
+	}
+}
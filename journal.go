@@ -0,0 +1,110 @@
+package backoff
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Journal results
+const (
+	journalSuccess = "success"
+	journalRetry   = "retry"
+	journalGiveUp  = "give up"
+)
+
+// WithJournal writes one line per retry decision to w: after a successful
+// attempt, before each backoff pause and when the loop gives up. Each line has
+// a timestamp, the attempt number, the result, the attempt error, the pause and,
+// when giving up, the reason. This is a cheap audit trail without a metrics
+// infrastructure. FormatCSV lines have no header row. Write errors are
+// ignored. w may be shared by Backoff instances. The journal is written by a
+// Middleware added to the middleware chain.
+func WithJournal(w io.Writer, format Format) Options {
+	return WithMiddleware(&journal{w: w, format: format, now: time.Now})
+}
+
+// journal is the Middleware of WithJournal
+type journal struct {
+	NopMiddleware
+	w      io.Writer
+	format Format
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+func (j *journal) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	j.write(a.Number, journalRetry, a.err, wait, nil)
+}
+
+func (j *journal) OnFinish(ctx context.Context, last *Attempt, err error) {
+	number, lastErr := 0, error(nil)
+	if last != nil {
+		number, lastErr = last.Number, unwrapPermanent(last.err)
+	}
+	if err == nil {
+		j.write(number, journalSuccess, nil, 0, nil)
+		return
+	}
+	j.write(number, journalGiveUp, lastErr, 0, err)
+}
+
+type journalEntryJSON struct {
+	Time        time.Time `json:"time"`
+	Attempt     int       `json:"attempt"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+	Wait        string    `json:"wait"`
+	WaitSeconds float64   `json:"wait_seconds"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// write writes a decision. It is safe to call on a nil journal.
+func (j *journal) write(attempt int, result string, err error, wait time.Duration, reason error) {
+	e := journalEntryJSON{
+		Time:        j.now(),
+		Attempt:     attempt,
+		Result:      result,
+		Wait:        wait.String(),
+		WaitSeconds: wait.Seconds(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if reason != nil {
+		e.Reason = reason.Error()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch j.format {
+	case FormatCSV:
+		cw := csv.NewWriter(j.w)
+		_ = cw.Write([]string{
+			e.Time.Format(time.RFC3339Nano),
+			strconv.Itoa(e.Attempt),
+			e.Result,
+			e.Error,
+			e.Wait,
+			strconv.FormatFloat(e.WaitSeconds, 'f', -1, 64),
+			e.Reason,
+		})
+		cw.Flush()
+	case FormatJSON:
+		_ = json.NewEncoder(j.w).Encode(e)
+	default:
+		line := fmt.Sprintf("%s attempt=%d result=%q wait=%s", e.Time.Format(time.RFC3339Nano), e.Attempt, e.Result, e.Wait)
+		if e.Error != "" {
+			line += fmt.Sprintf(" error=%q", e.Error)
+		}
+		if e.Reason != "" {
+			line += fmt.Sprintf(" reason=%q", e.Reason)
+		}
+		fmt.Fprintln(j.w, line)
+	}
+}
@@ -0,0 +1,84 @@
+package backoff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithJournal(t *testing.T) {
+	cases := map[string]struct {
+		format Format
+		want   string
+	}{
+		"Text": {
+			format: FormatText,
+			want: `2019-07-19T00:00:00Z attempt=1 result="retry" wait=500ms error="timeout"
+2019-07-19T00:00:00Z attempt=2 result="retry" wait=1s error="timeout"
+2019-07-19T00:00:00Z attempt=3 result="give up" wait=0s error="timeout" reason="all tries failed"
+`,
+		},
+		"CSV": {
+			format: FormatCSV,
+			want: `2019-07-19T00:00:00Z,1,retry,timeout,500ms,0.5,
+2019-07-19T00:00:00Z,2,retry,timeout,1s,1,
+2019-07-19T00:00:00Z,3,give up,timeout,0s,0,all tries failed
+`,
+		},
+		"JSON": {
+			format: FormatJSON,
+			want: `{"time":"2019-07-19T00:00:00Z","attempt":1,"result":"retry","error":"timeout","wait":"500ms","wait_seconds":0.5}
+{"time":"2019-07-19T00:00:00Z","attempt":2,"result":"retry","error":"timeout","wait":"1s","wait_seconds":1}
+{"time":"2019-07-19T00:00:00Z","attempt":3,"result":"give up","error":"timeout","wait":"0s","wait_seconds":0,"reason":"all tries failed"}
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var buf bytes.Buffer
+			bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc), WithJournal(&buf, tc.format))
+			bo.middleware[0].(*journal).now = func() time.Time { return time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC) }
+			err := bo.TryErr(context.Background(), 3, func(ctx context.Context) error {
+				return errors.New("timeout")
+			})
+
+			assert.ErrorIs(t, err, AllTriesFailed)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func Test_WithJournal_Success(t *testing.T) {
+	var buf bytes.Buffer
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc), WithJournal(&buf, FormatText))
+	bo.middleware[0].(*journal).now = func() time.Time { return time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC) }
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool { return true })
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2019-07-19T00:00:00Z attempt=1 result=\"success\" wait=0s\n", buf.String())
+}
+
+func Test_WithJournal_AttemptNumbering(t *testing.T) {
+	var buf bytes.Buffer
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc), WithJournal(&buf, FormatText),
+		WithAttemptNumbering(AttemptsMonotonic), WithAttemptsMade(4))
+	bo.middleware[0].(*journal).now = func() time.Time { return time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC) }
+	var numbers []int
+	err := bo.TryErrFrom(context.Background(), 3, func(ctx context.Context) error {
+		a, _ := ScheduleFromContext(ctx)
+		numbers = append(numbers, a.Number)
+		return errors.New("timeout")
+	}, 1, 500*time.Millisecond)
+
+	assert.ErrorIs(t, err, AllTriesFailed)
+	assert.Equal(t, []int{5, 6}, numbers)
+	assert.Equal(t, `2019-07-19T00:00:00Z attempt=5 result="retry" wait=1s error="timeout"
+2019-07-19T00:00:00Z attempt=6 result="give up" wait=0s error="timeout" reason="all tries failed"
+`, buf.String())
+}
@@ -0,0 +1,112 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+)
+
+// ConcurrencyLimiter is a semaphore whose limit is driven by the retry signal
+// of the Backoff instances that share it. Each retry lowers the limit by one
+// and each successful attempt raises it by one, always staying between the
+// minimum and maximum. Callers use Acquire around their work so concurrency
+// drops while a dependency is struggling and recovers as it heals.
+//
+// A ConcurrencyLimiter is safe for concurrent use.
+type ConcurrencyLimiter struct {
+	min int
+	max int
+
+	mu      sync.Mutex
+	limit   int
+	inUse   int
+	changed chan struct{}
+}
+
+// NewConcurrencyLimiter creates a new ConcurrencyLimiter starting at the
+// maximum limit. min is raised to 1 if lower, and max to min.
+func NewConcurrencyLimiter(min, max int) *ConcurrencyLimiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &ConcurrencyLimiter{
+		min:     min,
+		max:     max,
+		limit:   max,
+		changed: make(chan struct{}),
+	}
+}
+
+// WithConcurrencyLimiter reports every retry and success of the Backoff to the
+// ConcurrencyLimiter.
+func WithConcurrencyLimiter(cl *ConcurrencyLimiter) Options {
+	return func(bo *Backoff) {
+		bo.limiter = cl
+	}
+}
+
+// Acquire blocks until a slot is available under the current limit or the
+// context is done. The returned release function must be called once the work
+// is finished.
+func (c *ConcurrencyLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	for {
+		c.mu.Lock()
+		if c.inUse < c.limit {
+			c.inUse++
+			c.mu.Unlock()
+			var once sync.Once
+			return func() { once.Do(c.release) }, nil
+		}
+		changed := c.changed
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (c *ConcurrencyLimiter) Limit() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+func (c *ConcurrencyLimiter) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inUse--
+	c.notify()
+}
+
+// adjust changes the limit by delta. It is safe to call on a nil
+// ConcurrencyLimiter.
+func (c *ConcurrencyLimiter) adjust(delta int) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	limit := c.limit + delta
+	if limit < c.min {
+		limit = c.min
+	}
+	if limit > c.max {
+		limit = c.max
+	}
+	raised := limit > c.limit
+	c.limit = limit
+	if raised {
+		c.notify()
+	}
+}
+
+// notify wakes up all waiting Acquire calls. c.mu must be held.
+func (c *ConcurrencyLimiter) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
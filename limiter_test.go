@@ -0,0 +1,57 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_ConcurrencyLimiter_FollowsRetries(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cl := backoff.NewConcurrencyLimiter(2, 5)
+	bo := backoff.NewBackoff(shortInterval, backoff.WithConcurrencyLimiter(cl))
+
+	err := bo.TryErr(ctx, 10, failWith(assert.AnError, assert.AnError, assert.AnError, assert.AnError))
+	assert.NoError(t, err)
+	// four retries stop at the minimum, the success raises it by one
+	assert.Equal(t, 3, cl.Limit())
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, bo.TryErr(ctx, 1, failWith()))
+	}
+	assert.Equal(t, 5, cl.Limit())
+}
+
+func Test_ConcurrencyLimiter_Acquire(t *testing.T) {
+	cl := backoff.NewConcurrencyLimiter(1, 1)
+
+	release, err := cl.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cl.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	acquired := make(chan struct{})
+	go func() {
+		r, err := cl.Acquire(context.Background())
+		if err == nil {
+			r()
+		}
+		close(acquired)
+	}()
+	release()
+	release() // releasing twice is a no-op
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("waiting Acquire was not woken up")
+	}
+}
@@ -0,0 +1,64 @@
+package backoff
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// LoadAware is an Intervals decorator that backs off harder when the local
+// process is overloaded itself. Before each interval it probes the load, for
+// example the number of goroutines, CPU usage or a queue depth, and multiplies
+// the interval by load/threshold when the load is above the threshold, up to
+// maxFactor.
+type LoadAware struct {
+	intervals Intervals
+	probe     func() float64
+	threshold float64
+	maxFactor float64
+}
+
+var _ Intervals = LoadAware{}
+
+// NewLoadAware creates a new LoadAware decorator of iv. probe is called for
+// each interval and must be cheap and safe for concurrent use.
+func NewLoadAware(iv Intervals, probe func() float64, threshold, maxFactor float64) LoadAware {
+	return LoadAware{
+		intervals: iv,
+		probe:     probe,
+		threshold: threshold,
+		maxFactor: maxFactor,
+	}
+}
+
+// GoroutineLoad is a load probe that returns the number of goroutines.
+func GoroutineLoad() float64 {
+	return float64(runtime.NumGoroutine())
+}
+
+// Next returns the interval of the decorated series scaled by the load factor.
+func (l LoadAware) Next(i int8, last time.Duration) time.Duration {
+	next := l.intervals.Next(i, last)
+	return time.Duration(float64(next) * l.factor())
+}
+
+// factor returns the multiplier for the current load
+func (l LoadAware) factor() float64 {
+	if l.threshold <= 0 {
+		return 1
+	}
+	f := l.probe() / l.threshold
+	if f < 1 {
+		return 1
+	}
+	if f > l.maxFactor && l.maxFactor >= 1 {
+		return l.maxFactor
+	}
+	return f
+}
+
+// String describes the series, for example "load aware exponential x2 from
+// 500ms capped 20s up to x4".
+func (l LoadAware) String() string {
+	return fmt.Sprintf("load aware %s up to x%g", Describe(l.intervals), l.maxFactor)
+}
@@ -0,0 +1,48 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_LoadAware(t *testing.T) {
+	cases := map[string]struct {
+		load float64
+		want time.Duration
+	}{
+		"Below threshold": {
+			load: 50,
+			want: time.Second,
+		},
+		"At threshold": {
+			load: 100,
+			want: time.Second,
+		},
+		"Above threshold": {
+			load: 250,
+			want: 2500 * time.Millisecond,
+		},
+		"Capped": {
+			load: 1000,
+			want: 4 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			la := backoff.NewLoadAware(backoff.DefaultBinaryExponential(), func() float64 { return tc.load }, 100, 4)
+			assert.Equal(t, tc.want, la.Next(1, 0))
+		})
+	}
+}
+
+func Test_LoadAware_String(t *testing.T) {
+	la := backoff.NewLoadAware(backoff.DefaultBinaryExponential(), backoff.GoroutineLoad, 1000, 4)
+	assert.Equal(t, "load aware exponential x2 from 500ms capped 20s up to x4", backoff.Describe(la))
+	assert.Greater(t, backoff.GoroutineLoad(), float64(0))
+}
@@ -0,0 +1,134 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// LoadFunc loads the value for a key, for example from a database.
+type LoadFunc[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// Loader is a helper for the cache-aside pattern. On a cache miss, call Load to
+// get the value from the backing store. Load combines three protections:
+//
+//   - concurrent loads of the same key are deduplicated so only one goroutine
+//     calls the LoadFunc
+//   - a failing LoadFunc is retried with the Backoff using TryErr
+//   - when all tries fail, the error is cached for the key for a time to live
+//     that follows the negative Intervals series, so repeated Loads for a
+//     failing key return quickly instead of stampeding the backing store
+//
+// Loader does not cache successfully loaded values. That is left to the caller.
+type Loader[K comparable, V any] struct {
+	backoff     *Backoff
+	tries       int8
+	load        LoadFunc[K, V]
+	negativeTTL Intervals
+	now         func() time.Time
+
+	mu       sync.Mutex
+	calls    map[K]*loaderCall[V]
+	failures map[K]*negativeEntry
+}
+
+type loaderCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewLoader creates a new Loader. Each Load tries the LoadFunc up to `tries`
+// times using the Backoff. After a failed Load, the error is cached for the next
+// interval of negativeTTL. Consecutive failures for the same key advance the
+// negativeTTL series and a successful load resets it.
+func NewLoader[K comparable, V any](bo *Backoff, tries int8, load LoadFunc[K, V], negativeTTL Intervals) *Loader[K, V] {
+	return &Loader[K, V]{
+		backoff:     bo,
+		tries:       tries,
+		load:        load,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		calls:       make(map[K]*loaderCall[V]),
+		failures:    make(map[K]*negativeEntry),
+	}
+}
+
+// Load returns the value for key. If a load for the key is already in flight,
+// Load waits for it and returns its result. If the key recently failed to load,
+// Load returns the cached error without calling the LoadFunc. If ctx is done
+// while waiting for another load, Load returns BackoffContextTimeoutExceeded.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	var zero V
+	l.mu.Lock()
+	if f, ok := l.failures[key]; ok && l.now().Before(f.until) {
+		l.mu.Unlock()
+		return zero, f.err
+	}
+	if c, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return zero, BackoffContextTimeoutExceeded
+		case <-c.done:
+			return c.value, c.err
+		}
+	}
+	c := &loaderCall[V]{done: make(chan struct{})}
+	l.calls[key] = c
+	l.mu.Unlock()
+
+	defer func() {
+		// waiters get a *PanicError if the load panics, the panic continues
+		r := recover()
+		if r != nil {
+			c.value, c.err = zero, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+		l.mu.Lock()
+		if r == nil {
+			l.record(key, c.err)
+		}
+		delete(l.calls, key)
+		l.mu.Unlock()
+		close(c.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	c.err = l.backoff.TryErr(ctx, l.tries, func(ctx context.Context) error {
+		var err error
+		c.value, err = l.load(ctx, key)
+		return err
+	})
+	if c.err != nil {
+		c.value = zero
+	}
+	return c.value, c.err
+}
+
+// record updates the negative cache entry for key. Like NegativeCache, only
+// failures of the loads are cached, not the end of the context of the caller
+// that ran the load. l.mu must be held.
+func (l *Loader[K, V]) record(key K, err error) {
+	switch {
+	case err == nil:
+		delete(l.failures, key)
+	case errors.Is(err, AllTriesFailed), errors.Is(err, PermanentFailure):
+		f, ok := l.failures[key]
+		if !ok {
+			f = &negativeEntry{}
+			l.failures[key] = f
+		}
+		f.fail(l.negativeTTL, err, l.now())
+	}
+}
+
+// Forget removes the negative cache entry for key so the next Load calls the
+// LoadFunc again.
+func (l *Loader[K, V]) Forget(key K) {
+	l.mu.Lock()
+	delete(l.failures, key)
+	l.mu.Unlock()
+}
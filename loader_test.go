@@ -0,0 +1,171 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var loaderShortInterval = Exponential{
+	Base:    2 * time.Millisecond,
+	Unit:    time.Millisecond,
+	Initial: 1 * time.Millisecond,
+	Max:     20 * time.Millisecond,
+}
+
+func Test_Loader_DeduplicatesConcurrentLoads(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	l := NewLoader(NewBackoff(loaderShortInterval), 3, func(ctx context.Context, key string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return len(key), nil
+	}, DefaultBinaryExponential())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	values := make([]int, 5)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := l.Load(ctx, "four")
+			assert.NoError(t, err)
+			values[i] = v
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, []int{4, 4, 4, 4, 4}, values)
+}
+
+func Test_Loader_RetriesAndCachesFailures(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	loadErr := errors.New("db down")
+	fail := true
+	calls := 0
+	l := NewLoader(NewBackoff(loaderShortInterval), 2, func(ctx context.Context, key string) (string, error) {
+		calls++
+		if fail {
+			return "", loadErr
+		}
+		return "value", nil
+	}, DefaultBinaryExponential())
+	l.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// both tries fail and the error is cached for 0.5s
+	_, err := l.Load(ctx, "key")
+	assert.True(t, errors.Is(err, loadErr))
+	assert.Equal(t, 2, calls)
+
+	_, err = l.Load(ctx, "key")
+	assert.True(t, errors.Is(err, loadErr))
+	assert.Equal(t, 2, calls)
+
+	// the second failure is cached for 1s
+	now = now.Add(500 * time.Millisecond)
+	_, err = l.Load(ctx, "key")
+	assert.True(t, errors.Is(err, loadErr))
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, time.Second, l.failures["key"].ttl)
+
+	now = now.Add(999 * time.Millisecond)
+	_, err = l.Load(ctx, "key")
+	assert.True(t, errors.Is(err, loadErr))
+	assert.Equal(t, 4, calls)
+
+	// success resets the negative cache
+	fail = false
+	now = now.Add(time.Millisecond)
+	v, err := l.Load(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", v)
+	assert.Equal(t, 5, calls)
+	assert.NotContains(t, l.failures, "key")
+}
+
+func Test_Loader_Forget(t *testing.T) {
+	calls := 0
+	l := NewLoader(NewBackoff(loaderShortInterval), 1, func(ctx context.Context, key string) (string, error) {
+		calls++
+		return "", errors.New("fail")
+	}, DefaultBinaryExponential())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, _ = l.Load(ctx, "key")
+	_, _ = l.Load(ctx, "key")
+	assert.Equal(t, 1, calls)
+
+	l.Forget("key")
+	_, _ = l.Load(ctx, "key")
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Loader_DoesNotCacheContextErrors(t *testing.T) {
+	calls := 0
+	l := NewLoader(NewBackoff(loaderShortInterval), 2, func(ctx context.Context, key string) (string, error) {
+		calls++
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return key, nil
+	}, DefaultBinaryExponential())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := l.Load(ctx, "a")
+	assert.True(t, errors.Is(err, BackoffContextTimeoutExceeded))
+
+	v, err := l.Load(context.Background(), "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", v)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Loader_Panic(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	l := NewLoader(NewBackoff(loaderShortInterval), 1, func(ctx context.Context, key string) (string, error) {
+		close(started)
+		<-release
+		panic("boom")
+	}, DefaultBinaryExponential())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		defer func() { recover() }()
+		l.Load(ctx, "a")
+	}()
+	<-started
+	waiter := make(chan error)
+	go func() {
+		_, err := l.Load(ctx, "a")
+		waiter <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	var perr *PanicError
+	assert.True(t, errors.As(<-waiter, &perr))
+	assert.Equal(t, "boom", perr.Value)
+	l.mu.Lock()
+	assert.Empty(t, l.calls)
+	assert.Empty(t, l.failures)
+	l.mu.Unlock()
+}
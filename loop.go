@@ -0,0 +1,133 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+)
+
+// Loop is a retry loop that can be controlled while it runs. It can be paused
+// and resumed, and the remaining backoff pause can be skipped with RetryNow, for
+// example when an operator knows the dependency recovered.
+type Loop struct {
+	backoff *Backoff
+	tries   int8
+	fn      Operation
+
+	mu       sync.Mutex
+	resume   chan struct{} // non-nil while paused
+	kick     chan struct{}
+	attempts int // attempts of the finished Runs
+}
+
+// NewLoop creates a new Loop that calls fn with the Backoff up to `tries` times
+// when Run is called.
+func NewLoop(bo *Backoff, tries int8, fn Operation) *Loop {
+	return &Loop{
+		backoff: bo,
+		tries:   tries,
+		fn:      fn,
+		kick:    make(chan struct{}, 1),
+	}
+}
+
+// Run runs the retry loop until fn succeeds or the loop gives up. The returned
+// error is the same as TryErr.
+func (l *Loop) Run(ctx context.Context) error {
+	// a RetryNow from before Run does not skip the first pause
+	select {
+	case <-l.kick:
+	default:
+	}
+	bo := *l.backoff
+	bo.loop = l
+	return bo.tryErr(ctx, l.tries, l.fn, 0, 0)
+}
+
+// Pause stops the loop from starting new attempts until Resume is called. An
+// attempt already in progress is not interrupted and the backoff pause keeps
+// counting down while paused.
+func (l *Loop) Pause() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.resume == nil {
+		l.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused loop continue.
+func (l *Loop) Resume() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.resume != nil {
+		close(l.resume)
+		l.resume = nil
+	}
+}
+
+// Paused reports if the loop is paused.
+func (l *Loop) Paused() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.resume != nil
+}
+
+// RetryNow skips the remaining backoff pause so the next attempt starts
+// immediately. If called while an attempt is in progress, the pause after that
+// attempt is skipped. RetryNow does not resume a paused loop.
+func (l *Loop) RetryNow() {
+	select {
+	case l.kick <- struct{}{}:
+	default:
+		// a retry is already pending
+	}
+}
+
+// waitResumed blocks while the loop is paused. It is safe to call on a nil Loop.
+func (l *Loop) waitResumed(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	resume := l.resume
+	l.mu.Unlock()
+	if resume == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resume:
+		return nil
+	}
+}
+
+// retryNow returns the channel that skips the backoff pause. A nil Loop returns
+// a nil channel which blocks forever in a select.
+func (l *Loop) retryNow() <-chan struct{} {
+	if l == nil {
+		return nil
+	}
+	return l.kick
+}
+
+// Attempts returns the number of attempts made by the finished Runs. With
+// AttemptsMonotonic the attempts of a Run are numbered after them. It is safe
+// to call on a nil Loop.
+func (l *Loop) Attempts() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.attempts
+}
+
+// addAttempts counts the attempts of a Run. It is safe to call on a nil Loop.
+func (l *Loop) addAttempts(n int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.attempts += n
+}
@@ -0,0 +1,98 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Loop_RetryNow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var attempts int32
+	hour := backoff.Exponential{Base: 1, Unit: 1, Initial: time.Hour, Max: time.Hour}
+	loop := backoff.NewLoop(backoff.NewBackoff(hour), 3, func(ctx context.Context) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- loop.Run(ctx)
+	}()
+
+	for i := 0; i < 2; i++ {
+		for atomic.LoadInt32(&attempts) <= int32(i) {
+			time.Sleep(time.Millisecond)
+		}
+		loop.RetryNow()
+	}
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func Test_Loop_PauseResume(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var attempts int32
+	loop := backoff.NewLoop(backoff.NewBackoff(shortInterval), 5, func(ctx context.Context) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("fail")
+		}
+		return nil
+	})
+
+	loop.Pause()
+	assert.True(t, loop.Paused())
+	done := make(chan error)
+	go func() {
+		done <- loop.Run(ctx)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&attempts))
+
+	loop.Resume()
+	assert.False(t, loop.Paused())
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func Test_Loop_PausedContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	loop := backoff.NewLoop(backoff.NewBackoff(shortInterval), 5, func(ctx context.Context) error {
+		return nil
+	})
+	loop.Pause()
+
+	err := loop.Run(ctx)
+	assert.Equal(t, backoff.BackoffContextTimeoutExceeded, err)
+}
+
+func Test_Loop_StaleRetryNow(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	hour := backoff.Exponential{Base: 1, Unit: 1, Initial: time.Hour, Max: time.Hour}
+	loop := backoff.NewLoop(backoff.NewBackoff(hour), 2, func(ctx context.Context) error {
+		attempts++
+		return errors.New("fail")
+	})
+	// a RetryNow before Run does not skip the first pause
+	loop.RetryNow()
+	assert.True(t, errors.Is(loop.Run(ctx), backoff.BackoffContextTimeoutExceeded))
+	assert.Equal(t, 1, attempts)
+}
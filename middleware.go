@@ -0,0 +1,268 @@
+package backoff
+
+import (
+	"context"
+	"log"
+	"runtime/trace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Middleware observes the retry loop of a Try call. The methods are called in
+// this order:
+//
+//   - BeforeAttempt before each attempt. The returned context is passed to the
+//     attempt and to AfterAttempt.
+//   - AfterAttempt after each attempt with its error, nil on success.
+//   - BeforeSleep before each retry with its backoff pause, which may be
+//     zero, for example after WithReauthorizer refreshed the credentials.
+//   - OnFinish once when the call ends, with the last Attempt (nil if none
+//     was made) and the reason the loop stopped, like AllTriesFailed, or nil
+//     on success.
+//
+// A Middleware may be shared by concurrent Try calls, so per call state must
+// be kept in the context or derived from the Attempt. Embed NopMiddleware to
+// implement only some of the methods.
+type Middleware interface {
+	BeforeAttempt(ctx context.Context, a *Attempt) context.Context
+	AfterAttempt(ctx context.Context, a *Attempt, err error, took time.Duration)
+	BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration)
+	OnFinish(ctx context.Context, last *Attempt, err error)
+}
+
+// WithMiddleware adds middleware to the retry loop. BeforeAttempt and
+// BeforeSleep are called in the order the middleware was added, AfterAttempt
+// and OnFinish in reverse order, so the first middleware wraps the others.
+// WithMiddleware can be used more than once.
+func WithMiddleware(mw ...Middleware) Options {
+	return func(bo *Backoff) {
+		// copy so Backoff copies made for a single call do not share the array
+		bo.middleware = append(bo.middleware[:len(bo.middleware):len(bo.middleware)], mw...)
+	}
+}
+
+// NopMiddleware implements Middleware with methods that do nothing.
+type NopMiddleware struct{}
+
+var _ Middleware = NopMiddleware{}
+
+// BeforeAttempt returns ctx.
+func (NopMiddleware) BeforeAttempt(ctx context.Context, a *Attempt) context.Context {
+	return ctx
+}
+
+// AfterAttempt does nothing.
+func (NopMiddleware) AfterAttempt(ctx context.Context, a *Attempt, err error, took time.Duration) {}
+
+// BeforeSleep does nothing.
+func (NopMiddleware) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {}
+
+// OnFinish does nothing.
+func (NopMiddleware) OnFinish(ctx context.Context, last *Attempt, err error) {}
+
+// middlewares is the middleware chain of a Backoff
+type middlewares []Middleware
+
+func (m middlewares) beforeAttempt(ctx context.Context, a *Attempt) context.Context {
+	for _, mw := range m {
+		ctx = mw.BeforeAttempt(ctx, a)
+	}
+	return ctx
+}
+
+func (m middlewares) afterAttempt(ctx context.Context, a *Attempt, err error, took time.Duration) {
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].AfterAttempt(ctx, a, err, took)
+	}
+}
+
+func (m middlewares) beforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	for _, mw := range m {
+		mw.BeforeSleep(ctx, a, wait)
+	}
+}
+
+func (m middlewares) onFinish(ctx context.Context, last *Attempt, err error) {
+	for i := len(m) - 1; i >= 0; i-- {
+		m[i].OnFinish(ctx, last, err)
+	}
+}
+
+// LogMiddleware logs each backoff pause and the end of a Try call that gave up
+// to l.
+func LogMiddleware(l *log.Logger) Middleware {
+	return logMiddleware{l: l}
+}
+
+type logMiddleware struct {
+	NopMiddleware
+	l *log.Logger
+}
+
+func (m logMiddleware) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	m.l.Printf("backoff: attempt %s failed: %v, retrying in %s", a.ID, a.err, wait)
+}
+
+func (m logMiddleware) OnFinish(ctx context.Context, last *Attempt, err error) {
+	switch {
+	case err == nil:
+	case last == nil:
+		m.l.Printf("backoff: gave up before the first attempt: %v", err)
+	default:
+		m.l.Printf("backoff: retry %s gave up after %d attempts: %v: %v", last.RetryID, last.Number, err, last.err)
+	}
+}
+
+// Metrics is a Middleware that counts the attempts and outcomes of the Try
+// calls it observes. It is safe for concurrent use.
+type Metrics struct {
+	NopMiddleware
+
+	attempts  atomic.Int64
+	failures  atomic.Int64
+	pauses    atomic.Int64
+	waited    atomic.Int64
+	successes atomic.Int64
+	giveUps   atomic.Int64
+}
+
+// MetricsSnapshot holds the counters of Metrics.
+type MetricsSnapshot struct {
+	// Attempts is the number of attempts
+	Attempts int64
+	// Failures is the number of failed attempts
+	Failures int64
+	// Pauses is the number of backoff pauses
+	Pauses int64
+	// Waited is the sum of the backoff pauses
+	Waited time.Duration
+	// Successes is the number of Try calls that succeeded
+	Successes int64
+	// GiveUps is the number of Try calls that gave up
+	GiveUps int64
+}
+
+// NewMetrics creates a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Snapshot returns the current counters.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	return MetricsSnapshot{
+		Attempts:  m.attempts.Load(),
+		Failures:  m.failures.Load(),
+		Pauses:    m.pauses.Load(),
+		Waited:    time.Duration(m.waited.Load()),
+		Successes: m.successes.Load(),
+		GiveUps:   m.giveUps.Load(),
+	}
+}
+
+// AfterAttempt counts the attempt.
+func (m *Metrics) AfterAttempt(ctx context.Context, a *Attempt, err error, took time.Duration) {
+	m.attempts.Add(1)
+	if err != nil {
+		m.failures.Add(1)
+	}
+}
+
+// BeforeSleep counts the pause.
+func (m *Metrics) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	m.pauses.Add(1)
+	m.waited.Add(int64(wait))
+}
+
+// OnFinish counts the outcome of the Try call.
+func (m *Metrics) OnFinish(ctx context.Context, last *Attempt, err error) {
+	if err == nil {
+		m.successes.Add(1)
+		return
+	}
+	m.giveUps.Add(1)
+}
+
+// TraceMiddleware emits a runtime/trace region for each attempt and each
+// backoff pause and logs the failed attempts and the outcome of the Try call
+// under the category name.
+func TraceMiddleware(name string) Middleware {
+	return &traceMiddleware{name: name, pauses: make(map[string]*trace.Region)}
+}
+
+type traceMiddleware struct {
+	name string
+
+	mu sync.Mutex
+	// pauses holds the region of the running pause by RetryID
+	pauses map[string]*trace.Region
+}
+
+type traceRegionKey struct{}
+
+func (m *traceMiddleware) BeforeAttempt(ctx context.Context, a *Attempt) context.Context {
+	m.endPause(a.RetryID)
+	return context.WithValue(ctx, traceRegionKey{}, trace.StartRegion(ctx, "attempt"))
+}
+
+func (m *traceMiddleware) AfterAttempt(ctx context.Context, a *Attempt, err error, took time.Duration) {
+	if region, ok := ctx.Value(traceRegionKey{}).(*trace.Region); ok {
+		region.End()
+	}
+}
+
+func (m *traceMiddleware) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	trace.Logf(ctx, m.name, "attempt %d failed, pausing %s", a.Number, wait)
+	m.mu.Lock()
+	m.pauses[a.RetryID] = trace.StartRegion(ctx, "backoff pause")
+	m.mu.Unlock()
+}
+
+func (m *traceMiddleware) OnFinish(ctx context.Context, last *Attempt, err error) {
+	if last != nil {
+		m.endPause(last.RetryID)
+	}
+	if err != nil {
+		trace.Logf(ctx, m.name, "gave up: %v", err)
+		return
+	}
+	trace.Log(ctx, m.name, "succeeded")
+}
+
+// endPause ends the pause region of the Try call retryID, if any
+func (m *traceMiddleware) endPause(retryID string) {
+	m.mu.Lock()
+	region, ok := m.pauses[retryID]
+	delete(m.pauses, retryID)
+	m.mu.Unlock()
+	if ok {
+		region.End()
+	}
+}
+
+// RecordMiddleware captures every attempt and computed wait into r. It is the
+// middleware added by WithRecorder.
+func RecordMiddleware(r *Recorder) Middleware {
+	return recordMiddleware{r: r}
+}
+
+type recordMiddleware struct {
+	NopMiddleware
+	r *Recorder
+}
+
+func (m recordMiddleware) BeforeSleep(ctx context.Context, a *Attempt, wait time.Duration) {
+	m.r.record(a.started, a.Number, a.err, a.took, wait)
+}
+
+func (m recordMiddleware) OnFinish(ctx context.Context, last *Attempt, err error) {
+	if last != nil && !last.paused {
+		m.r.record(last.started, last.Number, unwrapPermanent(last.err), last.took, 0)
+	}
+}
+
+// unwrapPermanent returns the error marked with Permanent
+func unwrapPermanent(err error) error {
+	err, _ = isPermanent(err)
+	return err
+}
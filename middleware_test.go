@@ -0,0 +1,138 @@
+package backoff_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+// eventMiddleware records the calls it receives prefixed with its name
+type eventMiddleware struct {
+	name   string
+	events *[]string
+}
+
+func (m eventMiddleware) BeforeAttempt(ctx context.Context, a *backoff.Attempt) context.Context {
+	*m.events = append(*m.events, fmt.Sprintf("%s before %d", m.name, a.Number))
+	return ctx
+}
+
+func (m eventMiddleware) AfterAttempt(ctx context.Context, a *backoff.Attempt, err error, took time.Duration) {
+	*m.events = append(*m.events, fmt.Sprintf("%s after %d %v", m.name, a.Number, err))
+}
+
+func (m eventMiddleware) BeforeSleep(ctx context.Context, a *backoff.Attempt, wait time.Duration) {
+	*m.events = append(*m.events, fmt.Sprintf("%s sleep %s", m.name, wait))
+}
+
+func (m eventMiddleware) OnFinish(ctx context.Context, last *backoff.Attempt, err error) {
+	*m.events = append(*m.events, fmt.Sprintf("%s finish %d %v", m.name, last.Number, err))
+}
+
+func Test_WithMiddleware(t *testing.T) {
+	var events []string
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithMiddleware(eventMiddleware{name: "a", events: &events}),
+		backoff.WithMiddleware(eventMiddleware{name: "b", events: &events}),
+	)
+	err := bo.TryErr(context.Background(), 3, failWith(assert.AnError))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"a before 1",
+		"b before 1",
+		"b after 1 " + assert.AnError.Error(),
+		"a after 1 " + assert.AnError.Error(),
+		"a sleep 1ms",
+		"b sleep 1ms",
+		"a before 2",
+		"b before 2",
+		"b after 2 <nil>",
+		"a after 2 <nil>",
+		"b finish 2 <nil>",
+		"a finish 2 <nil>",
+	}, events)
+}
+
+func Test_Metrics(t *testing.T) {
+	m := backoff.NewMetrics()
+	bo := backoff.NewBackoff(shortInterval, backoff.WithMiddleware(m))
+
+	require.NoError(t, bo.TryErr(context.Background(), 3, failWith(assert.AnError)))
+	require.Error(t, bo.TryErr(context.Background(), 2, failWith(assert.AnError, assert.AnError)))
+
+	assert.Equal(t, backoff.MetricsSnapshot{
+		Attempts:  4,
+		Failures:  3,
+		Pauses:    2,
+		Waited:    2 * time.Millisecond,
+		Successes: 1,
+		GiveUps:   1,
+	}, m.Snapshot())
+}
+
+func Test_LogMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	bo := backoff.NewBackoff(shortInterval, backoff.WithMiddleware(backoff.LogMiddleware(log.New(&buf, "", 0))))
+
+	err := bo.TryErr(context.Background(), 2, failWith(errors.New("refused"), errors.New("refused")))
+	require.Error(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^backoff: attempt [0-9a-f]+-1 failed: refused, retrying in 1ms$`, string(lines[0]))
+	assert.Regexp(t, `^backoff: retry [0-9a-f]+ gave up after 2 attempts: all tries failed: refused$`, string(lines[1]))
+}
+
+func Test_RecordMiddleware(t *testing.T) {
+	cases := map[string]struct {
+		tries int8
+		errs  []error
+	}{
+		"Success": {
+			tries: 3,
+			errs:  []error{assert.AnError},
+		},
+		"All tries failed": {
+			tries: 2,
+			errs:  []error{assert.AnError, assert.AnError},
+		},
+		"Permanent failure": {
+			tries: 3,
+			errs:  []error{assert.AnError, backoff.Permanent(assert.AnError)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			want := backoff.NewRecorder(10)
+			got := backoff.NewRecorder(10)
+			_ = backoff.NewBackoff(shortInterval, backoff.WithRecorder(want)).TryErr(context.Background(), tc.tries, failWith(tc.errs...))
+			_ = backoff.NewBackoff(shortInterval, backoff.WithMiddleware(backoff.RecordMiddleware(got))).TryErr(context.Background(), tc.tries, failWith(tc.errs...))
+
+			strip := func(records []backoff.Record) []backoff.Record {
+				for i := range records {
+					records[i].Start = time.Time{}
+					records[i].Duration = 0
+				}
+				return records
+			}
+			assert.Equal(t, strip(want.Snapshot()), strip(got.Snapshot()))
+		})
+	}
+}
+
+func Test_TraceMiddleware(t *testing.T) {
+	bo := backoff.NewBackoff(shortInterval, backoff.WithMiddleware(backoff.TraceMiddleware("test")))
+	assert.NoError(t, bo.TryErr(context.Background(), 3, failWith(assert.AnError)))
+}
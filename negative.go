@@ -0,0 +1,114 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RecentlyFailed is returned by NegativeCache when the key failed recently
+const RecentlyFailed = Error("recently failed")
+
+// negativeEntry is a negative cache entry. `count` is the number of
+// consecutive failures and `ttl` the last time to live.
+type negativeEntry struct {
+	err   error
+	until time.Time
+	count int8
+	ttl   time.Duration
+}
+
+// fail records a failure with err and advances the time to live series
+func (f *negativeEntry) fail(ttl Intervals, err error, now time.Time) {
+	f.ttl = ttl.Next(f.count, f.ttl)
+	if f.count < InfiniteTries {
+		f.count++
+	}
+	f.err = err
+	f.until = now.Add(f.ttl)
+}
+
+// NegativeCache remembers keys whose retry loop recently gave up, so repeated
+// Try and TryErr calls for a known bad target fail fast instead of going
+// through the whole schedule again. The time to live of a failed key follows
+// an Intervals series: consecutive failures for the same key advance the
+// series and a success resets it.
+//
+// Only loops that gave up with AllTriesFailed or PermanentFailure are cached.
+// A done context says nothing about the target and is not cached.
+type NegativeCache struct {
+	backoff *Backoff
+	ttl     Intervals
+	now     func() time.Time
+
+	mu       sync.Mutex
+	failures map[string]*negativeEntry
+}
+
+// NewNegativeCache creates a new NegativeCache that runs retry loops with the
+// provided Backoff and caches failures for the intervals of ttl.
+func NewNegativeCache(bo *Backoff, ttl Intervals) *NegativeCache {
+	return &NegativeCache{
+		backoff:  bo,
+		ttl:      ttl,
+		now:      time.Now,
+		failures: make(map[string]*negativeEntry),
+	}
+}
+
+// Try returns RecentlyFailed if key failed recently, and calls Backoff.Try
+// otherwise.
+func (n *NegativeCache) Try(ctx context.Context, key string, tries int8, fn Completable) error {
+	if n.cached(key) != nil {
+		return RecentlyFailed
+	}
+	err := n.backoff.Try(ctx, tries, fn)
+	n.record(key, err)
+	return err
+}
+
+// TryErr returns an error matching RecentlyFailed and wrapping the cached error
+// if key failed recently, and calls Backoff.TryErr otherwise.
+func (n *NegativeCache) TryErr(ctx context.Context, key string, tries int8, fn Operation) error {
+	if err := n.cached(key); err != nil {
+		return fmt.Errorf("%w: %w", RecentlyFailed, err)
+	}
+	err := n.backoff.TryErr(ctx, tries, fn)
+	n.record(key, err)
+	return err
+}
+
+// Forget removes the cached failure of key.
+func (n *NegativeCache) Forget(key string) {
+	n.mu.Lock()
+	delete(n.failures, key)
+	n.mu.Unlock()
+}
+
+// cached returns the cached error of key if it has not expired
+func (n *NegativeCache) cached(key string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if f, ok := n.failures[key]; ok && n.now().Before(f.until) {
+		return f.err
+	}
+	return nil
+}
+
+func (n *NegativeCache) record(key string, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch {
+	case err == nil:
+		delete(n.failures, key)
+	case errors.Is(err, AllTriesFailed), errors.Is(err, PermanentFailure):
+		f, ok := n.failures[key]
+		if !ok {
+			f = &negativeEntry{}
+			n.failures[key] = f
+		}
+		f.fail(n.ttl, err, n.now())
+	}
+}
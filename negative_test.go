@@ -0,0 +1,77 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NegativeCache(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	ttl := Exponential{Base: 2 * time.Second, Unit: time.Second, Initial: time.Second, Max: time.Minute}
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc))
+	nc := NewNegativeCache(bo, ttl)
+	nc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	calls := 0
+	succeed := false
+	fn := func(ctx context.Context) bool {
+		calls++
+		return succeed
+	}
+
+	assert.Equal(t, AllTriesFailed, nc.Try(ctx, "a", 3, fn))
+	assert.Equal(t, 3, calls)
+
+	// fail fast within the ttl, other keys are not affected
+	assert.Equal(t, RecentlyFailed, nc.Try(ctx, "a", 3, fn))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, AllTriesFailed, nc.Try(ctx, "b", 1, fn))
+	assert.Equal(t, 4, calls)
+
+	// the second failure doubles the ttl
+	now = now.Add(time.Second)
+	assert.Equal(t, AllTriesFailed, nc.Try(ctx, "a", 1, fn))
+	now = now.Add(time.Second)
+	assert.Equal(t, RecentlyFailed, nc.Try(ctx, "a", 1, fn))
+
+	// a success resets the cache
+	now = now.Add(time.Second)
+	succeed = true
+	assert.NoError(t, nc.Try(ctx, "a", 1, fn))
+	succeed = false
+	assert.Equal(t, AllTriesFailed, nc.Try(ctx, "a", 1, fn))
+	nc.Forget("a")
+	assert.Equal(t, AllTriesFailed, nc.Try(ctx, "a", 1, fn))
+}
+
+func Test_NegativeCache_TryErr(t *testing.T) {
+	bo := NewBackoff(DefaultBinaryExponential(), withAfterFunc(immediateAfterFunc))
+	nc := NewNegativeCache(bo, DefaultBinaryExponential())
+	notFound := errors.New("not found")
+
+	err := nc.TryErr(context.Background(), "a", 3, func(ctx context.Context) error {
+		return Permanent(notFound)
+	})
+	assert.ErrorIs(t, err, PermanentFailure)
+
+	err = nc.TryErr(context.Background(), "a", 3, func(ctx context.Context) error {
+		t.Fatal("cached key must not be called")
+		return nil
+	})
+	assert.ErrorIs(t, err, RecentlyFailed)
+	assert.ErrorIs(t, err, notFound)
+
+	// a done context is not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = nc.TryErr(ctx, "b", 3, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, BackoffContextTimeoutExceeded)
+	assert.Nil(t, nc.cached("b"))
+}
@@ -0,0 +1,94 @@
+package backoff
+
+import "context"
+
+// UnboundedAmplification is the amplification reported to WithOnNested when
+// an enclosing Try call, or the nested call itself, uses InfiniteTries.
+const UnboundedAmplification = -1
+
+// nesting describes the Try calls a context runs in. `amplification` is the
+// product of the tries of the enclosing Try calls, the number of times the
+// innermost function can be called for a single outer call.
+type nesting struct {
+	depth         int
+	amplification int
+}
+
+type nestingKey struct{}
+
+func nestingFrom(ctx context.Context) nesting {
+	if n, ok := ctx.Value(nestingKey{}).(nesting); ok {
+		return n
+	}
+	return nesting{amplification: 1}
+}
+
+// WithOnNested calls fn when a Try call of the Backoff runs inside another Try
+// call of this package, for example to log a warning. depth is 1 for a Try
+// inside a single outer Try and amplification is the number of times the
+// function could be called for one call of the outermost Try, or
+// UnboundedAmplification. Zero tries count as the single attempt they make.
+// Nested retries across layers are a classic cause of request amplification.
+func WithOnNested(fn func(depth, amplification int)) Options {
+	return func(bo *Backoff) {
+		bo.onNested = fn
+	}
+}
+
+// WithNestedFlatten makes a Try call that runs inside another Try call make a
+// single attempt, leaving the retries to the outer call.
+func WithNestedFlatten() Options {
+	return func(bo *Backoff) {
+		bo.nestedFlatten = true
+	}
+}
+
+// WithNestedCap lowers the tries of a Try call that runs inside other Try
+// calls so the amplification stays at most max. At least one attempt is made.
+func WithNestedCap(max int) Options {
+	return func(bo *Backoff) {
+		bo.nestedCap = max
+	}
+}
+
+// nest applies the nested options and returns ctx marked for the attempts
+func (b *Backoff) nest(ctx context.Context, tries int8) (context.Context, int8) {
+	n := nestingFrom(ctx)
+	if n.depth > 0 {
+		switch amplification := amplify(n.amplification, tries); {
+		case b.nestedFlatten:
+			tries = 1
+		case b.nestedCap > 0 && (amplification == UnboundedAmplification || amplification > b.nestedCap):
+			capped := 1
+			if n.amplification != UnboundedAmplification {
+				capped = b.nestedCap / n.amplification
+			}
+			switch {
+			case capped < 1:
+				capped = 1
+			case capped >= InfiniteTries:
+				capped = InfiniteTries - 1
+			}
+			tries = int8(capped)
+		}
+		if b.onNested != nil {
+			b.onNested(n.depth, amplify(n.amplification, tries))
+		}
+	}
+	n.depth++
+	n.amplification = amplify(n.amplification, tries)
+	return context.WithValue(ctx, nestingKey{}, n), tries
+}
+
+// amplify returns the amplification of a Try call with tries inside calls
+// with amplification a
+func amplify(a int, tries int8) int {
+	switch {
+	case a == UnboundedAmplification, tries == InfiniteTries:
+		return UnboundedAmplification
+	case tries < 1:
+		// zero tries make a single attempt
+		return a
+	}
+	return a * int(tries)
+}
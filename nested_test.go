@@ -0,0 +1,146 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_NestedRetries(t *testing.T) {
+	type nested struct{ depth, amplification int }
+
+	cases := map[string]struct {
+		option         backoff.Options
+		wantInnerCalls int
+		wantNested     []nested
+	}{
+		"Allow": {
+			option:         backoff.WithNestedCap(0),
+			wantInnerCalls: 5 * 5,
+		},
+		"Flatten": {
+			option:         backoff.WithNestedFlatten(),
+			wantInnerCalls: 5,
+		},
+		"Cap": {
+			option:         backoff.WithNestedCap(10),
+			wantInnerCalls: 5 * 2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var got []nested
+			onNested := backoff.WithOnNested(func(depth, amplification int) {
+				got = append(got, nested{depth, amplification})
+			})
+			outer := backoff.NewBackoff(backoff.Immediate(0).Intervals)
+			inner := backoff.NewBackoff(backoff.Immediate(0).Intervals, onNested, tc.option)
+
+			innerCalls := 0
+			err := outer.Try(ctx, 5, func(ctx context.Context) bool {
+				_ = inner.Try(ctx, 5, func(ctx context.Context) bool {
+					innerCalls++
+					return false
+				})
+				return false
+			})
+
+			assert.Equal(t, backoff.AllTriesFailed, err)
+			assert.Equal(t, tc.wantInnerCalls, innerCalls)
+			assert.Len(t, got, 5)
+			assert.Equal(t, nested{1, tc.wantInnerCalls}, got[0])
+		})
+	}
+}
+
+func Test_NestedRetries_NotNested(t *testing.T) {
+	called := false
+	bo := backoff.NewBackoff(shortInterval, backoff.WithOnNested(func(depth, amplification int) {
+		called = true
+	}))
+	err := bo.Try(context.Background(), 1, func(ctx context.Context) bool { return true })
+
+	assert.NoError(t, err)
+	assert.False(t, called)
+}
+
+func Test_NestedRetries_Amplification(t *testing.T) {
+	cases := map[string]struct {
+		outerTries        int8
+		innerTries        int8
+		option            backoff.Options
+		wantInnerCalls    int
+		wantAmplification int
+	}{
+		"Zero outer tries": {
+			outerTries:        0,
+			innerTries:        5,
+			wantInnerCalls:    5,
+			wantAmplification: 5,
+		},
+		"Infinite outer tries": {
+			outerTries:        backoff.InfiniteTries,
+			innerTries:        5,
+			wantInnerCalls:    5,
+			wantAmplification: backoff.UnboundedAmplification,
+		},
+		"Infinite inner tries": {
+			outerTries:        5,
+			innerTries:        backoff.InfiniteTries,
+			wantInnerCalls:    5,
+			wantAmplification: backoff.UnboundedAmplification,
+		},
+		"Infinite outer tries capped": {
+			outerTries:        backoff.InfiniteTries,
+			innerTries:        5,
+			option:            backoff.WithNestedCap(10),
+			wantInnerCalls:    1,
+			wantAmplification: backoff.UnboundedAmplification,
+		},
+		"Infinite inner tries capped": {
+			outerTries:        5,
+			innerTries:        backoff.InfiniteTries,
+			option:            backoff.WithNestedCap(10),
+			wantInnerCalls:    2,
+			wantAmplification: 10,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var got []int
+			opts := []backoff.Options{backoff.WithOnNested(func(depth, amplification int) {
+				got = append(got, amplification)
+			})}
+			if tc.option != nil {
+				opts = append(opts, tc.option)
+			}
+			outer := backoff.NewBackoff(backoff.Immediate(0).Intervals)
+			inner := backoff.NewBackoff(backoff.Immediate(0).Intervals, opts...)
+
+			innerCalls := 0
+			err := outer.Try(ctx, tc.outerTries, func(ctx context.Context) bool {
+				_ = inner.Try(ctx, tc.innerTries, func(ctx context.Context) bool {
+					innerCalls++
+					return innerCalls == 5
+				})
+				return true
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantInnerCalls, innerCalls)
+			assert.Equal(t, []int{tc.wantAmplification}, got)
+		})
+	}
+}
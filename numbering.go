@@ -0,0 +1,53 @@
+package backoff
+
+// AttemptNumbering decides how the attempts of a call are numbered, see
+// WithAttemptNumbering.
+type AttemptNumbering int
+
+const (
+	// AttemptsPerCall numbers the attempts of each call from 1
+	AttemptsPerCall AttemptNumbering = iota
+	// AttemptsFromIndex numbers the attempts of a resumed call from its
+	// index, so TryFrom with i = 3 numbers its first attempt 4
+	AttemptsFromIndex
+	// AttemptsMonotonic numbers the attempts after the ones made before: the
+	// count set with WithAttemptsMade, for example stored with the resume
+	// state, and the attempts of the earlier Runs of a Loop
+	AttemptsMonotonic
+)
+
+// WithAttemptNumbering sets how Attempt.Number, the Recorder, the journal and
+// WithBeforeRetry number the attempts. Numbering that continues across
+// resumes keeps alerts like "attempt > N" meaningful after a restart. The
+// default is AttemptsPerCall. The numbering does not change the tries or the
+// series.
+func WithAttemptNumbering(n AttemptNumbering) Options {
+	return func(bo *Backoff) {
+		bo.numbering = n
+	}
+}
+
+// WithAttemptsMade sets the number of attempts made before the call, for
+// example by an earlier process, for AttemptsMonotonic. Store the count with
+// the resume state, like backoffstate.State.Attempts, adding the attempts of
+// each call that gave up, RetryError.Attempts, before storing it again.
+func WithAttemptsMade(n int) Options {
+	return func(bo *Backoff) {
+		if n < 0 {
+			n = 0
+		}
+		bo.attemptsMade = n
+	}
+}
+
+// attemptOffset returns the number of the attempt before the first attempt
+// of a call starting at index initI
+func (b *Backoff) attemptOffset(initI int8) int {
+	switch b.numbering {
+	case AttemptsFromIndex:
+		return int(initI)
+	case AttemptsMonotonic:
+		return b.attemptsMade + b.loop.Attempts()
+	}
+	return 0
+}
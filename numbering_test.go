@@ -0,0 +1,77 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithAttemptNumbering(t *testing.T) {
+	tests := map[string]struct {
+		options []backoff.Options
+		i       int8
+		want    []int
+	}{
+		"per call": {
+			i:    2,
+			want: []int{1, 2},
+		},
+		"from index": {
+			options: []backoff.Options{backoff.WithAttemptNumbering(backoff.AttemptsFromIndex)},
+			i:       2,
+			want:    []int{3, 4},
+		},
+		"monotonic": {
+			options: []backoff.Options{
+				backoff.WithAttemptNumbering(backoff.AttemptsMonotonic),
+				backoff.WithAttemptsMade(7),
+			},
+			i:    2,
+			want: []int{8, 9},
+		},
+		"attempts made without monotonic": {
+			options: []backoff.Options{backoff.WithAttemptsMade(7)},
+			want:    []int{1, 2},
+		},
+	}
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			var got []int
+			bo := backoff.NewBackoff(shortInterval, tc.options...)
+			_ = bo.TryErrFrom(ctx, 4, func(ctx context.Context) error {
+				a, _ := backoff.ScheduleFromContext(ctx)
+				got = append(got, a.Number)
+				if len(got) < 2 {
+					return assert.AnError
+				}
+				return nil
+			}, tc.i, 0)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func Test_Loop_MonotonicAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var got []int
+	bo := backoff.NewBackoff(shortInterval, backoff.WithAttemptNumbering(backoff.AttemptsMonotonic))
+	loop := backoff.NewLoop(bo, 2, func(ctx context.Context) error {
+		a, _ := backoff.ScheduleFromContext(ctx)
+		got = append(got, a.Number)
+		return assert.AnError
+	})
+	assert.Error(t, loop.Run(ctx))
+	assert.Error(t, loop.Run(ctx))
+	assert.Equal(t, []int{1, 2, 3, 4}, got)
+	assert.Equal(t, 4, loop.Attempts())
+}
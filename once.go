@@ -0,0 +1,83 @@
+package backoff
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Once runs an operation with retries until it completes successfully once,
+// like sync.Once with a Backoff. It is useful to lazily establish a shared
+// resource, for example a connection, from many goroutines.
+//
+// Concurrent callers of Do share one retry loop. When the loop fails, the
+// callers receive its error and a later Do starts a new loop. After a success,
+// Do returns nil without calling the function again.
+type Once struct {
+	backoff *Backoff
+	tries   int8
+
+	mu   sync.Mutex
+	done bool
+	call *singleCall
+}
+
+// NewOnce creates a new Once that tries the operation up to `tries` times per
+// Do using the Backoff.
+func NewOnce(bo *Backoff, tries int8) *Once {
+	return &Once{backoff: bo, tries: tries}
+}
+
+// Do calls Backoff.TryErr with fn unless fn already succeeded, in which case it
+// returns nil. If a call is already in flight, Do waits for it and returns its
+// result. If ctx is done while waiting, Do returns
+// BackoffContextTimeoutExceeded. If fn panics, the panic continues in the
+// caller that started the loop, waiting callers get a *PanicError and Once is
+// not done.
+//
+// As with Single, the context and function of the caller that starts the loop
+// are used for the whole loop.
+func (o *Once) Do(ctx context.Context, fn Operation) error {
+	o.mu.Lock()
+	if o.done {
+		o.mu.Unlock()
+		return nil
+	}
+	if c := o.call; c != nil {
+		o.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return BackoffContextTimeoutExceeded
+		case <-c.done:
+			return c.err
+		}
+	}
+	c := &singleCall{done: make(chan struct{})}
+	o.call = c
+	o.mu.Unlock()
+
+	defer func() {
+		// waiters get a *PanicError if fn panics, the panic continues
+		r := recover()
+		if r != nil {
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+		o.mu.Lock()
+		o.done = c.err == nil
+		o.call = nil
+		o.mu.Unlock()
+		close(c.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	c.err = o.backoff.TryErr(ctx, o.tries, fn)
+	return c.err
+}
+
+// Done reports if an operation completed successfully.
+func (o *Once) Done() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.done
+}
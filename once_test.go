@@ -0,0 +1,105 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Once_CompletesOnce(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	once := backoff.NewOnce(backoff.NewBackoff(shortInterval), 3)
+	var calls int32
+	fn := func(ctx context.Context) error {
+		if atomic.AddInt32(&calls, 1) < 2 {
+			return assert.AnError
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = once.Do(ctx, fn)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.True(t, once.Done())
+	assert.NoError(t, once.Do(ctx, fn))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func Test_Once_RetriesAfterFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	once := backoff.NewOnce(backoff.NewBackoff(shortInterval), 2)
+	err := once.Do(ctx, failWith(assert.AnError, assert.AnError))
+	assert.True(t, errors.Is(err, backoff.AllTriesFailed))
+	assert.False(t, once.Done())
+
+	var calls int
+	err = once.Do(ctx, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, once.Done())
+}
+
+func Test_Once_WaiterContext(t *testing.T) {
+	once := backoff.NewOnce(backoff.NewBackoff(shortInterval), 1)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go once.Do(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, backoff.BackoffContextTimeoutExceeded, once.Do(ctx, nil))
+	close(release)
+}
+
+func Test_Once_Panic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	once := backoff.NewOnce(backoff.NewBackoff(shortInterval), 1)
+	assert.Panics(t, func() {
+		once.Do(ctx, func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.False(t, once.Done())
+
+	calls := 0
+	assert.NoError(t, once.Do(ctx, func(ctx context.Context) error {
+		calls++
+		return nil
+	}))
+	assert.Equal(t, 1, calls)
+	assert.True(t, once.Done())
+}
@@ -0,0 +1,111 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Operation is a function that returns nil when it completes successfully or an
+// error describing why the attempt failed. Like Completable it should terminate
+// early if the context.Done() channel is closed.
+type Operation func(ctx context.Context) error
+
+// TryErr is like Try but calls an Operation that reports failures as errors.
+//
+// If an attempt returns an error wrapped with Permanent, the returned error
+// matches PermanentFailure and wraps the unwrapped attempt error.
+//
+// If the function provided with WithBeforeRetry returns an error, the returned
+// error matches RetryAborted and wraps that error.
+//
+// If all tries fail, the returned error matches AllTriesFailed with errors.Is
+// and wraps the error of the last attempt. If the context is done before an
+// attempt succeeds, the returned error matches BackoffContextTimeoutExceeded
+// and wraps the error of the last attempt.
+//
+// With WithCollectErrors the returned error wraps the errors of all attempts
+// instead of only the last one, followed by the error that aborted the loop.
+//
+// Once an attempt was made, the returned error is a *RetryError.
+//
+// Options apply to this call only, like with Try.
+func (b *Backoff) TryErr(ctx context.Context, tries int8, fn Operation, options ...Options) error {
+	return b.with(options).tryErr(ctx, tries, fn, 0, 0)
+}
+
+func (b *Backoff) tryErr(ctx context.Context, tries int8, fn Operation, initI int8, initWait time.Duration) error {
+	err, lastErr, h := b.run(ctx, tries, fn, initI, initWait)
+	if err == nil {
+		return nil
+	}
+	if b.collectErrors {
+		errs := h.errs
+		if err == RetryAborted {
+			// keep the cause of the abort, it is not an attempt error
+			errs = append(errs[:len(errs):len(errs)], lastErr)
+		}
+		lastErr = errors.Join(errs...)
+	}
+	if lastErr == nil {
+		// stopped before the first attempt
+		b.giveUp(h, err)
+		return err
+	}
+	retryErr := &RetryError{
+		Reason:   err,
+		Err:      lastErr,
+		Policy:   Describe(b.intervals),
+		Attempts: h.attempts,
+		Pauses:   h.pauses,
+		Waited:   h.waited,
+		Start:    h.start,
+		End:      time.Now(),
+	}
+	b.giveUp(h, retryErr)
+	return retryErr
+}
+
+// permanentError marks an error that should stop the retry loop
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string {
+	return p.err.Error()
+}
+
+func (p *permanentError) Unwrap() error {
+	return p.err
+}
+
+// isPermanent reports if err is or wraps an error marked with Permanent. It
+// returns err without the mark: the marked error, or err itself when the mark
+// is wrapped, for example with fmt.Errorf, since its message then already
+// reads like the marked error.
+func isPermanent(err error) (error, bool) {
+	if perm, ok := err.(*permanentError); ok {
+		return perm.err, true
+	}
+	var perm *permanentError
+	return err, errors.As(err, &perm)
+}
+
+// Permanent wraps err so that TryErr stops without making further tries. The
+// returned error from TryErr matches PermanentFailure and wraps err. Permanent
+// returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// WithCollectErrors makes TryErr return the errors of every attempt joined with
+// errors.Join instead of only the last error. This is useful to see if the
+// failures were the same for all attempts or varied across attempts.
+func WithCollectErrors() Options {
+	return func(bo *Backoff) {
+		bo.collectErrors = true
+	}
+}
@@ -0,0 +1,221 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+var shortInterval = backoff.Exponential{
+	Base:    2 * time.Millisecond,
+	Unit:    time.Millisecond,
+	Initial: 1 * time.Millisecond,
+	Max:     20 * time.Millisecond,
+}
+
+// returns an Operation that fails with errs in order and then succeeds
+func failWith(errs ...error) backoff.Operation {
+	i := 0
+	return func(ctx context.Context) error {
+		if i >= len(errs) {
+			return nil
+		}
+		err := errs[i]
+		i++
+		return err
+	}
+}
+
+func Test_TryErr(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	err3 := errors.New("err3")
+	errAbort := errors.New("abort")
+
+	cases := map[string]struct {
+		fn         backoff.Operation
+		tries      int8
+		options    []backoff.Options
+		wantNil    bool
+		wantIs     []error
+		wantIsNot  []error
+		wantErrMsg string
+	}{
+		"Succeed Immediately": {
+			fn:      failWith(),
+			tries:   3,
+			wantNil: true,
+		},
+		"Succeed After 2 tries": {
+			fn:      failWith(err1),
+			tries:   3,
+			wantNil: true,
+		},
+		"Fail wraps last error": {
+			fn:         failWith(err1, err2, err3),
+			tries:      3,
+			wantIs:     []error{backoff.AllTriesFailed, err3},
+			wantIsNot:  []error{err1, err2},
+			wantErrMsg: "all tries failed: err3",
+		},
+		"Fail with collected errors": {
+			fn:         failWith(err1, err2, err3),
+			tries:      3,
+			options:    []backoff.Options{backoff.WithCollectErrors()},
+			wantIs:     []error{backoff.AllTriesFailed, err1, err2, err3},
+			wantErrMsg: "all tries failed: err1\nerr2\nerr3",
+		},
+		"Aborted with collected errors": {
+			fn:    failWith(err1, err2, err3),
+			tries: 3,
+			options: []backoff.Options{
+				backoff.WithCollectErrors(),
+				backoff.WithBeforeRetry(func(ctx context.Context, attempt int) error {
+					if attempt == 2 {
+						return errAbort
+					}
+					return nil
+				}),
+			},
+			wantIs:     []error{backoff.RetryAborted, err1, err2, errAbort},
+			wantIsNot:  []error{err3},
+			wantErrMsg: "retry aborted: err1\nerr2\nabort",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := backoff.NewBackoff(shortInterval, tc.options...)
+			err := bo.TryErr(ctx, tc.tries, tc.fn)
+
+			if tc.wantNil {
+				assert.NoError(t, err)
+				return
+			}
+			for _, want := range tc.wantIs {
+				assert.True(t, errors.Is(err, want), "want %v in %v", want, err)
+			}
+			for _, want := range tc.wantIsNot {
+				assert.False(t, errors.Is(err, want), "unexpected %v in %v", want, err)
+			}
+			assert.EqualError(t, err, tc.wantErrMsg)
+		})
+	}
+}
+
+func Test_TryErr_ContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	bo := backoff.NewBackoff(backoff.DefaultBinaryExponential())
+	attempt := 0
+	err := bo.TryErr(ctx, backoff.InfiniteTries, func(ctx context.Context) error {
+		attempt++
+		return fmt.Errorf("attempt %d", attempt)
+	})
+
+	assert.True(t, errors.Is(err, backoff.BackoffContextTimeoutExceeded))
+	assert.EqualError(t, err, "backoff context timeout exceeded: attempt 1")
+}
+
+func Test_TryErr_Permanent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval)
+	cause := errors.New("not found")
+	attempts := 0
+	err := bo.TryErr(ctx, 5, func(ctx context.Context) error {
+		attempts++
+		return backoff.Permanent(cause)
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.True(t, errors.Is(err, backoff.PermanentFailure))
+	assert.True(t, errors.Is(err, cause))
+	assert.EqualError(t, err, "permanent failure: not found")
+}
+
+func Test_TryErr_WrappedPermanent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval)
+	cause := errors.New("not found")
+	attempts := 0
+	err := bo.TryErr(ctx, 5, func(ctx context.Context) error {
+		attempts++
+		return fmt.Errorf("get user: %w", backoff.Permanent(cause))
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.True(t, errors.Is(err, backoff.PermanentFailure))
+	assert.True(t, errors.Is(err, cause))
+	assert.EqualError(t, err, "permanent failure: get user: not found")
+}
+
+func Test_WithBeforeRetry(t *testing.T) {
+	abort := errors.New("rollback failed")
+
+	cases := map[string]struct {
+		hookErrAt    int
+		wantAttempts int
+		wantHooks    []int
+		wantIs       []error
+	}{
+		"Called between attempts": {
+			wantAttempts: 3,
+			wantHooks:    []int{1, 2},
+			wantIs:       []error{backoff.AllTriesFailed},
+		},
+		"Aborts the loop": {
+			hookErrAt:    2,
+			wantAttempts: 2,
+			wantHooks:    []int{1, 2},
+			wantIs:       []error{backoff.RetryAborted, abort},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var hooks []int
+			bo := backoff.NewBackoff(shortInterval, backoff.WithBeforeRetry(func(ctx context.Context, attempt int) error {
+				hooks = append(hooks, attempt)
+				if attempt == tc.hookErrAt {
+					return abort
+				}
+				return nil
+			}))
+			attempts := 0
+			err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+				attempts++
+				return errors.New("fail")
+			})
+
+			assert.Equal(t, tc.wantAttempts, attempts)
+			assert.Equal(t, tc.wantHooks, hooks)
+			for _, want := range tc.wantIs {
+				assert.True(t, errors.Is(err, want), "want %v in %v", want, err)
+			}
+		})
+	}
+}
+
+func Test_WithBeforeRetry_Try(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval, backoff.WithBeforeRetry(func(ctx context.Context, attempt int) error {
+		return errors.New("abort")
+	}))
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool { return false })
+	assert.Equal(t, backoff.RetryAborted, err)
+}
@@ -0,0 +1,52 @@
+package backoff
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy decides how a recovered panic in an attempt is treated.
+type PanicPolicy int
+
+const (
+	// PanicRetry treats a recovered panic like any other failed attempt
+	PanicRetry PanicPolicy = iota
+	// PanicStop treats a recovered panic as a permanent failure
+	PanicStop
+)
+
+// PanicError is the error an attempt fails with when it panics and panics are
+// recovered with WithRecoverPanics.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("attempt panicked: %v", p.Value)
+}
+
+// WithRecoverPanics recovers a panic inside a Completable or Operation and
+// converts it into a *PanicError. With PanicRetry the attempt counts as failed
+// and the loop continues. With PanicStop the loop stops immediately with a
+// PermanentFailure. Long lived loops should not be killed by a panic in a
+// single attempt.
+func WithRecoverPanics(policy PanicPolicy) Options {
+	return func(bo *Backoff) {
+		bo.recoverPanics = true
+		bo.panicPolicy = policy
+	}
+}
+
+func (b *Backoff) panicError(r interface{}) error {
+	err := &PanicError{
+		Value: r,
+		Stack: debug.Stack(),
+	}
+	if b.panicPolicy == PanicStop {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithRecoverPanics(t *testing.T) {
+	cases := map[string]struct {
+		policy       backoff.PanicPolicy
+		tries        int8
+		wantErr      error
+		wantAttempts int
+	}{
+		"Retry after panic": {
+			policy:       backoff.PanicRetry,
+			tries:        3,
+			wantErr:      nil,
+			wantAttempts: 2,
+		},
+		"Stop after panic": {
+			policy:       backoff.PanicStop,
+			tries:        3,
+			wantErr:      backoff.PermanentFailure,
+			wantAttempts: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			bo := backoff.NewBackoff(shortInterval, backoff.WithRecoverPanics(tc.policy))
+			attempts := 0
+			err := bo.Try(ctx, tc.tries, func(ctx context.Context) bool {
+				attempts++
+				if attempts == 1 {
+					panic("boom")
+				}
+				return true
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantAttempts, attempts)
+		})
+	}
+}
+
+func Test_WithRecoverPanics_TryErrReturnsPanicError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval, backoff.WithRecoverPanics(backoff.PanicStop))
+	err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	assert.True(t, errors.Is(err, backoff.PermanentFailure))
+	var perr *backoff.PanicError
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, "boom", perr.Value)
+	assert.NotEmpty(t, perr.Stack)
+	assert.EqualError(t, err, "permanent failure: attempt panicked: boom")
+}
@@ -0,0 +1,170 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// PickerConfig configures a Picker.
+type PickerConfig[B comparable] struct {
+	// Backends are the backends to pick from
+	Backends []B
+	// Intervals is the backoff series of a failing backend
+	Intervals Intervals
+	// Probe optionally checks the health of a backend. While Run is running,
+	// backends whose probe fails are skipped.
+	Probe func(ctx context.Context, backend B) error
+	// ProbeIntervals is the backoff series between probes of an unhealthy
+	// backend. Defaults to Intervals.
+	ProbeIntervals Intervals
+	// ProbePeriod is the pause between probes of a healthy backend. Defaults
+	// to DefaultProbePeriod.
+	ProbePeriod time.Duration
+}
+
+// Picker is a client-side load balancer. It picks among backends, skipping
+// those in backoff after a failure and preferring those with a higher recent
+// success rate. It is useful for clients without a service mesh.
+type Picker[B comparable] struct {
+	cfg  PickerConfig[B]
+	rand *rand.Rand
+	now  func() time.Time
+
+	mu       sync.Mutex
+	backends []*pickerBackend[B]
+}
+
+type pickerBackend[B comparable] struct {
+	backend B
+	success float64 // moving average of the success rate
+	i       int8
+	last    time.Duration
+	until   time.Time // end of the backoff
+	prober  *Prober
+}
+
+// pickerDecay is the weight of the previous success rate in the moving
+// average
+const pickerDecay = 0.8
+
+// pickerMinWeight keeps backends with a low success rate pickable so they can
+// recover
+const pickerMinWeight = 0.05
+
+// NewPicker creates a new Picker.
+func NewPicker[B comparable](cfg PickerConfig[B]) *Picker[B] {
+	if cfg.ProbeIntervals == nil {
+		cfg.ProbeIntervals = cfg.Intervals
+	}
+	if cfg.ProbePeriod <= 0 {
+		cfg.ProbePeriod = DefaultProbePeriod
+	}
+	p := &Picker[B]{
+		cfg:  cfg,
+		rand: rand.New(newLockedSource()),
+		now:  time.Now,
+	}
+	for _, backend := range cfg.Backends {
+		p.backends = append(p.backends, &pickerBackend[B]{backend: backend, success: 1})
+	}
+	return p
+}
+
+// Pick returns a backend and a function to report the result of the request
+// to it. Report a nil error on success. A reported error puts the backend in
+// backoff following the Intervals series, a success ends it.
+//
+// Pick chooses randomly among the available backends, weighted by their recent
+// success rate. When no backend is available, Pick returns the one whose
+// backoff ends first rather than failing.
+func (p *Picker[B]) Pick() (backend B, report func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var available []*pickerBackend[B]
+	var total float64
+	var soonest *pickerBackend[B]
+	for _, b := range p.backends {
+		if soonest == nil || b.until.Before(soonest.until) {
+			soonest = b
+		}
+		if b.until.After(now) || (b.prober != nil && !b.prober.Healthy()) {
+			continue
+		}
+		available = append(available, b)
+		total += b.weight()
+	}
+	picked := soonest
+	if len(available) > 0 {
+		r := p.rand.Float64() * total
+		for _, b := range available {
+			picked = b
+			if r -= b.weight(); r < 0 {
+				break
+			}
+		}
+	}
+	if picked == nil {
+		return backend, func(error) {}
+	}
+	return picked.backend, func(err error) { p.report(picked, err) }
+}
+
+func (b *pickerBackend[B]) weight() float64 {
+	if b.success < pickerMinWeight {
+		return pickerMinWeight
+	}
+	return b.success
+}
+
+func (p *Picker[B]) report(b *pickerBackend[B], err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b.success *= pickerDecay
+	if err == nil {
+		b.success += 1 - pickerDecay
+		b.i, b.last, b.until = 0, 0, time.Time{}
+		return
+	}
+	b.last = p.cfg.Intervals.Next(b.i, b.last)
+	if b.i < InfiniteTries {
+		b.i++
+	}
+	b.until = p.now().Add(b.last)
+}
+
+// Run probes the backends with a Prober each until ctx is done and returns the
+// context error. It returns immediately without a Probe.
+func (p *Picker[B]) Run(ctx context.Context) error {
+	if p.cfg.Probe == nil {
+		return nil
+	}
+	var wg sync.WaitGroup
+	p.mu.Lock()
+	for _, b := range p.backends {
+		backend := b.backend
+		b.prober = NewProber(ProberConfig{
+			Probe: func(ctx context.Context) error {
+				return p.cfg.Probe(ctx, backend)
+			},
+			Intervals: p.cfg.ProbeIntervals,
+			Period:    p.cfg.ProbePeriod,
+			Jitter:    0.1,
+		})
+		wg.Add(1)
+		go func(prober *Prober) {
+			defer wg.Done()
+			prober.Run(ctx)
+		}(b.prober)
+	}
+	p.mu.Unlock()
+	wg.Wait()
+	p.mu.Lock()
+	for _, b := range p.backends {
+		b.prober = nil
+	}
+	p.mu.Unlock()
+	return ctx.Err()
+}
@@ -0,0 +1,88 @@
+package backoff_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Picker_SkipsBackendsInBackoff(t *testing.T) {
+	picker := backoff.NewPicker(backoff.PickerConfig[string]{
+		Backends:  []string{"a", "b", "c"},
+		Intervals: backoff.DefaultBinaryExponential(),
+	})
+
+	// fail a and c
+	failed := map[string]bool{}
+	for len(failed) < 2 {
+		backend, report := picker.Pick()
+		if backend == "b" {
+			report(nil)
+			continue
+		}
+		report(assert.AnError)
+		failed[backend] = true
+	}
+	for n := 0; n < 20; n++ {
+		backend, report := picker.Pick()
+		assert.Equal(t, "b", backend)
+		report(nil)
+	}
+
+	// with all backends in backoff the one whose backoff ends first is picked
+	backend, report := picker.Pick()
+	report(assert.AnError)
+	assert.Equal(t, "b", backend)
+	backend, _ = picker.Pick()
+	assert.NotEqual(t, "b", backend)
+}
+
+func Test_Picker_Probe(t *testing.T) {
+	picker := backoff.NewPicker(backoff.PickerConfig[string]{
+		Backends:  []string{"up", "down"},
+		Intervals: shortInterval,
+		Probe: func(ctx context.Context, backend string) error {
+			if backend == "down" {
+				return assert.AnError
+			}
+			return nil
+		},
+		ProbePeriod: time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- picker.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		backend, _ := picker.Pick()
+		return backend == "up"
+	}, time.Second, time.Millisecond)
+	for n := 0; n < 20; n++ {
+		backend, _ := picker.Pick()
+		assert.Equal(t, "up", backend)
+	}
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func Test_Picker_DefaultProbePeriod(t *testing.T) {
+	var probes int32
+	picker := backoff.NewPicker(backoff.PickerConfig[string]{
+		Backends:  []string{"a"},
+		Intervals: shortInterval,
+		Probe: func(ctx context.Context, backend string) error {
+			atomic.AddInt32(&probes, 1)
+			return nil
+		},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, picker.Run(ctx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&probes))
+}
@@ -0,0 +1,163 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Policy is a named combination of an Intervals series and a number of tries.
+type Policy struct {
+	// Name identifies the policy in logs
+	Name      string
+	Intervals Intervals
+	// Tries is the `tries` argument used by Try and TryErr
+	Tries int8
+}
+
+// NewBackoff creates a new Backoff that uses the Intervals of the policy.
+func (p Policy) NewBackoff(options ...Options) *Backoff {
+	return NewBackoff(p.Intervals, options...)
+}
+
+// Try calls Try on a new Backoff using the Intervals and Tries of the policy.
+func (p Policy) Try(ctx context.Context, fn Completable, options ...Options) error {
+	return p.NewBackoff(options...).Try(ctx, p.Tries, fn)
+}
+
+// TryErr calls TryErr on a new Backoff using the Intervals and Tries of the
+// policy.
+func (p Policy) TryErr(ctx context.Context, fn Operation, options ...Options) error {
+	return p.NewBackoff(options...).TryErr(ctx, p.Tries, fn)
+}
+
+// PresetAPIClient is a policy for calls to remote APIs. It makes 5 tries with
+// the series 100ms, 200ms, 400ms, 800ms, each adjusted by a random value
+// between +/- 50ms, and is capped at 10s.
+func PresetAPIClient() Policy {
+	return preset("api-client", 100*time.Millisecond, 10*time.Second, 50*time.Millisecond, 5)
+}
+
+// PresetDatabase is a policy for database queries and transactions. It makes 8
+// tries with the series 50ms, 100ms, 200ms, ... capped at 5s, each adjusted by
+// a random value between +/- 25ms.
+func PresetDatabase() Policy {
+	return preset("database", 50*time.Millisecond, 5*time.Second, 25*time.Millisecond, 8)
+}
+
+// PresetMessageBroker is a policy for long lived consumers and producers that
+// should keep trying until the context is done. It makes InfiniteTries with the
+// series 1s, 2s, 4s, ... capped at 1m, each adjusted by a random value between
+// +/- 500ms.
+func PresetMessageBroker() Policy {
+	return preset("message-broker", time.Second, time.Minute, 500*time.Millisecond, InfiniteTries)
+}
+
+// PresetUserFacing is a policy for requests where a user is waiting. It makes 3
+// tries with the series 50ms, 100ms capped at 400ms, each adjusted by a random
+// value between +/- 25ms, so giving up takes well under a second.
+func PresetUserFacing() Policy {
+	return preset("user-facing", 50*time.Millisecond, 400*time.Millisecond, 25*time.Millisecond, 3)
+}
+
+// None is a policy that calls the function once and never waits. Use it to
+// write code uniformly against Policy when retries are disabled by
+// configuration.
+func None() Policy {
+	return Policy{Name: "none", Intervals: immediate{}, Tries: 1}
+}
+
+// Immediate is a policy that makes n tries without waiting between them.
+func Immediate(n int8) Policy {
+	return Policy{Name: "immediate", Intervals: immediate{}, Tries: n}
+}
+
+// immediate is an Intervals that never waits
+type immediate struct{}
+
+func (immediate) Next(i int8, last time.Duration) time.Duration {
+	return 0
+}
+
+func (immediate) String() string {
+	return "immediate"
+}
+
+// preset creates a named policy. The presets are vetted by the tests rather
+// than NewPolicy so they do not log on every call.
+func preset(name string, initial, max, jitter time.Duration, tries int8) Policy {
+	return Policy{
+		Name: name,
+		Intervals: ExponentialJitter{
+			Exponential: Exponential{
+				Base:    2 * time.Millisecond,
+				Unit:    time.Millisecond,
+				Initial: initial,
+				Max:     max,
+			},
+			JitterMax: jitter,
+			Rand:      rand.New(newLockedSource()),
+		},
+		Tries: tries,
+	}
+}
+
+// lockedSource is a rand.Source that is safe for concurrent use, so a preset
+// can be shared by Backoff instances in different goroutines.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func newLockedSource() *lockedSource {
+	seed := time.Now().UnixNano()
+	if r, err := newRand(); err == nil {
+		seed = r.Int63()
+	}
+	return &lockedSource{src: rand.NewSource(seed)}
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+type policyKey struct{}
+
+// WithPolicy returns a copy of ctx that overrides the retry behavior of Try and
+// TryErr calls lower in the stack: they use the Intervals and Tries of p
+// instead of their own. A nil Intervals keeps the series of the Backoff. For
+// example a request marked latency critical can disable retries with:
+//
+//	ctx = backoff.WithPolicy(ctx, backoff.Policy{Tries: 1})
+func WithPolicy(ctx context.Context, p Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// PolicyFromContext returns the policy set with WithPolicy.
+func PolicyFromContext(ctx context.Context) (Policy, bool) {
+	p, ok := ctx.Value(policyKey{}).(Policy)
+	return p, ok
+}
+
+// withContextPolicy applies the policy of ctx, if any, to a copy of b.
+func (b *Backoff) withContextPolicy(ctx context.Context, tries int8) (*Backoff, int8) {
+	p, ok := PolicyFromContext(ctx)
+	if !ok {
+		return b, tries
+	}
+	if p.Intervals != nil {
+		c := *b
+		c.intervals = p.Intervals
+		b = &c
+	}
+	return b, p.Tries
+}
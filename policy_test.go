@@ -0,0 +1,157 @@
+package backoff_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Presets(t *testing.T) {
+	cases := map[string]struct {
+		policy    backoff.Policy
+		wantTries int8
+		wantIv    string
+	}{
+		"APIClient": {
+			policy:    backoff.PresetAPIClient(),
+			wantTries: 5,
+			wantIv:    "exponential x2 from 100ms capped 10s ±50ms jitter",
+		},
+		"Database": {
+			policy:    backoff.PresetDatabase(),
+			wantTries: 8,
+			wantIv:    "exponential x2 from 50ms capped 5s ±25ms jitter",
+		},
+		"MessageBroker": {
+			policy:    backoff.PresetMessageBroker(),
+			wantTries: backoff.InfiniteTries,
+			wantIv:    "exponential x2 from 1s capped 1m0s ±500ms jitter",
+		},
+		"UserFacing": {
+			policy:    backoff.PresetUserFacing(),
+			wantTries: 3,
+			wantIv:    "exponential x2 from 50ms capped 400ms ±25ms jitter",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.wantTries, tc.policy.Tries)
+			assert.Equal(t, tc.wantIv, backoff.Describe(tc.policy.Intervals))
+			assert.NotEmpty(t, tc.policy.Name)
+		})
+	}
+}
+
+func Test_Policy_ConcurrentUse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	policy := backoff.PresetUserFacing()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calls := 0
+			err := policy.Try(ctx, func(ctx context.Context) bool {
+				calls++
+				return calls == 2
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_WithPolicy(t *testing.T) {
+	cases := map[string]struct {
+		override  *backoff.Policy
+		wantCalls int
+		wantWait  time.Duration // pause after the first attempt
+		wantErr   error
+	}{
+		"No override": {
+			wantCalls: 5,
+			wantWait:  time.Millisecond,
+			wantErr:   backoff.AllTriesFailed,
+		},
+		"Disable retries": {
+			override:  &backoff.Policy{Tries: 1},
+			wantCalls: 1,
+			wantErr:   backoff.AllTriesFailed,
+		},
+		"Override intervals": {
+			override: &backoff.Policy{
+				Intervals: backoff.NewRampUp(shortInterval, 0),
+				Tries:     2,
+			},
+			wantCalls: 2,
+			wantErr:   backoff.AllTriesFailed,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if tc.override != nil {
+				ctx = backoff.WithPolicy(ctx, *tc.override)
+			}
+			calls := 0
+			rec := backoff.NewRecorder(10)
+			err := backoff.NewBackoff(shortInterval, backoff.WithRecorder(rec)).Try(ctx, 5, func(ctx context.Context) bool {
+				calls++
+				return false
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantWait, rec.Snapshot()[0].Wait)
+		})
+	}
+}
+
+func Test_NoneAndImmediate(t *testing.T) {
+	cases := map[string]struct {
+		policy    backoff.Policy
+		wantCalls int
+	}{
+		"None": {
+			policy:    backoff.None(),
+			wantCalls: 1,
+		},
+		"Immediate": {
+			policy:    backoff.Immediate(4),
+			wantCalls: 4,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			calls := 0
+			rec := backoff.NewRecorder(10)
+			err := tc.policy.Try(ctx, func(ctx context.Context) bool {
+				calls++
+				return false
+			}, backoff.WithRecorder(rec))
+
+			assert.Equal(t, backoff.AllTriesFailed, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			for _, r := range rec.Snapshot() {
+				assert.Zero(t, r.Wait)
+			}
+			assert.Equal(t, "immediate", backoff.Describe(tc.policy.Intervals))
+		})
+	}
+}
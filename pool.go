@@ -0,0 +1,377 @@
+package backoff
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Job is a unit of work of a Pool.
+type Job[T any] struct {
+	// ID identifies the job in a PoolStore
+	ID string
+	// Payload is the value passed to the PoolFunc
+	Payload T
+	// Attempts is the number of attempts made
+	Attempts int
+	// Errors holds the error of each failed attempt in order
+	Errors []error
+	// NextRun is when the next attempt is due
+	NextRun time.Time
+	// Priority orders the jobs that are due, higher first. Jobs with the
+	// same priority run in the order they became due.
+	Priority int
+
+	i    int8
+	last time.Duration
+	seq  uint64 // submission order
+}
+
+// PoolFull is returned by Submit when the Pool holds MaxJobs jobs, and is
+// recorded as the last error of a job dropped to make room
+const PoolFull = Error("pool full")
+
+// OverflowPolicy decides what Submit does when the Pool holds MaxJobs jobs.
+type OverflowPolicy int
+
+const (
+	// OverflowReject makes Submit return PoolFull
+	OverflowReject OverflowPolicy = iota
+	// OverflowDropOldest drops the oldest pending job to make room and passes
+	// it to the DeadLetter
+	OverflowDropOldest
+	// OverflowBlock makes Submit wait for room until its context is done
+	OverflowBlock
+)
+
+// PoolFunc processes the payload of a job. A returned error schedules a retry
+// after a backoff pause unless it is marked with Permanent.
+type PoolFunc[T any] func(ctx context.Context, payload T) error
+
+// DeadLetter receives the jobs a Pool gave up on, because all tries failed or
+// an attempt returned a Permanent error, so failed work is never silently
+// dropped. Implement it to forward the jobs to a queue of your own.
+type DeadLetter[T any] interface {
+	DeadLetter(ctx context.Context, job Job[T])
+}
+
+// DeadLetterFunc adapts a function to DeadLetter.
+type DeadLetterFunc[T any] func(ctx context.Context, job Job[T])
+
+// DeadLetter calls f(ctx, job).
+func (f DeadLetterFunc[T]) DeadLetter(ctx context.Context, job Job[T]) {
+	f(ctx, job)
+}
+
+// DeadLetterChan returns a DeadLetter that sends the jobs to ch. The send
+// blocks the worker until ch has room or the Pool context is done. A job that
+// cannot be sent before the Pool context is done is dropped, so give ch enough
+// room or drain it until Run returns.
+func DeadLetterChan[T any](ch chan<- Job[T]) DeadLetter[T] {
+	return DeadLetterFunc[T](func(ctx context.Context, job Job[T]) {
+		select {
+		case ch <- job:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// PoolConfig configures a Pool.
+type PoolConfig[T any] struct {
+	// Func processes the jobs
+	Func PoolFunc[T]
+	// Intervals is the backoff series of each job
+	Intervals Intervals
+	// Tries is the number of tries of each job
+	Tries int8
+	// Workers is the number of jobs processed concurrently. Defaults to 1.
+	Workers int
+	// DeadLetter receives the jobs that failed. Without it failed jobs are
+	// dropped.
+	DeadLetter DeadLetter[T]
+	// MaxJobs limits the jobs in the Pool, pending or being attempted, so
+	// the retries do not grow without bound while a dependency is down.
+	// Zero does not limit the jobs.
+	MaxJobs int
+	// Overflow decides what Submit does when the Pool holds MaxJobs jobs
+	Overflow OverflowPolicy
+	// Store persists the pending jobs so they survive a restart, see
+	// Restore. Codec converts the payloads for the Store and defaults to
+	// JSONCodec. Errors of the Store after Submit are ignored and the job
+	// stays in memory.
+	Store PoolStore
+	Codec Codec[T]
+}
+
+// Pool processes jobs with a number of workers and retries failed jobs after
+// a backoff pause without blocking a worker during the pause. Create it with
+// NewPool and start the workers with Run.
+type Pool[T any] struct {
+	cfg PoolConfig[T]
+
+	mu        sync.Mutex
+	scheduled *jobQueue[T] // jobs waiting for their next run, by time
+	ready     *jobQueue[T] // jobs that are due, by priority
+	wake      chan struct{}
+	now       func() time.Time
+	jobs      int             // jobs in the Pool
+	ids       map[string]bool // IDs of the jobs in the Pool
+	seq       uint64          // last submission
+	space     chan struct{}   // closed when a job leaves the Pool
+}
+
+// NewPool creates a new Pool.
+func NewPool[T any](cfg PoolConfig[T]) *Pool[T] {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.Store != nil && cfg.Codec == nil {
+		cfg.Codec = JSONCodec[T]{}
+	}
+	return &Pool[T]{
+		cfg:       cfg,
+		scheduled: &jobQueue[T]{less: runsBefore[T]},
+		ready:     &jobQueue[T]{less: higherPriority[T]},
+		wake:      make(chan struct{}, 1),
+		now:       time.Now,
+		space:     make(chan struct{}),
+		ids:       make(map[string]bool),
+	}
+}
+
+// Submit adds a job for payload with priority 0. Its first attempt is due
+// immediately.
+func (p *Pool[T]) Submit(ctx context.Context, payload T) error {
+	return p.SubmitWithPriority(ctx, payload, 0)
+}
+
+// SubmitWithPriority adds a job for payload with a priority. When workers are
+// scarce the due jobs with a higher priority run first, so the retries of
+// important jobs preempt the first attempts of less important ones.
+//
+// When the Pool holds MaxJobs jobs, the Overflow policy applies.
+func (p *Pool[T]) SubmitWithPriority(ctx context.Context, payload T, priority int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	job := &Job[T]{ID: newRetryID(), Payload: payload, NextRun: p.now(), Priority: priority}
+	if err := p.save(ctx, job); err != nil {
+		return err
+	}
+	for {
+		p.mu.Lock()
+		if p.cfg.MaxJobs <= 0 || p.jobs < p.cfg.MaxJobs {
+			p.jobs++
+			p.add(job)
+			p.mu.Unlock()
+			p.signal()
+			return nil
+		}
+		switch p.cfg.Overflow {
+		case OverflowDropOldest:
+			dropped := p.dropOldest()
+			if dropped == nil {
+				// all jobs are being attempted
+				p.mu.Unlock()
+				p.forget(ctx, job)
+				return PoolFull
+			}
+			p.add(job)
+			p.mu.Unlock()
+			p.signal()
+			p.forget(ctx, dropped)
+			dropped.Errors = append(dropped.Errors, PoolFull)
+			p.deadLetter(ctx, dropped)
+			return nil
+		case OverflowBlock:
+			space := p.space
+			p.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				p.forget(context.WithoutCancel(ctx), job)
+				return ctx.Err()
+			case <-space:
+			}
+		default:
+			p.mu.Unlock()
+			p.forget(ctx, job)
+			return PoolFull
+		}
+	}
+}
+
+// add schedules a new job. The caller holds p.mu.
+func (p *Pool[T]) add(job *Job[T]) {
+	p.ids[job.ID] = true
+	p.seq++
+	job.seq = p.seq
+	heap.Push(p.scheduled, job)
+}
+
+// dropOldest removes the pending job submitted first. The caller holds p.mu.
+func (p *Pool[T]) dropOldest() *Job[T] {
+	var oldest *Job[T]
+	var from *jobQueue[T]
+	var at int
+	for _, q := range []*jobQueue[T]{p.scheduled, p.ready} {
+		for i, job := range q.jobs {
+			if oldest == nil || job.seq < oldest.seq {
+				oldest, from, at = job, q, i
+			}
+		}
+	}
+	if oldest != nil {
+		heap.Remove(from, at)
+		delete(p.ids, oldest.ID)
+	}
+	return oldest
+}
+
+// done removes a finished job from the Pool
+func (p *Pool[T]) done(job *Job[T]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.ids, job.ID)
+	p.jobs--
+	close(p.space)
+	p.space = make(chan struct{})
+}
+
+func (p *Pool[T]) deadLetter(ctx context.Context, job *Job[T]) {
+	if p.cfg.DeadLetter != nil {
+		p.cfg.DeadLetter.DeadLetter(ctx, *job)
+	}
+}
+
+// Pending returns the number of jobs waiting for an attempt.
+func (p *Pool[T]) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.scheduled.Len() + p.ready.Len()
+}
+
+// Run processes jobs until ctx is done and returns the context error. Jobs that
+// are still pending stay in the Pool.
+func (p *Pool[T]) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for n := 0; n < p.cfg.Workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (p *Pool[T]) work(ctx context.Context) {
+	for {
+		job, wait := p.next()
+		if job == nil {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-p.wake:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+		p.attempt(ctx, job)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// idleWait is how long an idle worker waits when no job is pending
+const idleWait = time.Minute
+
+// next pops the next due job, or returns how long to wait for one
+func (p *Pool[T]) next() (*Job[T], time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	for p.scheduled.Len() > 0 && !p.scheduled.jobs[0].NextRun.After(now) {
+		heap.Push(p.ready, heap.Pop(p.scheduled))
+	}
+	if p.ready.Len() > 0 {
+		return heap.Pop(p.ready).(*Job[T]), 0
+	}
+	if p.scheduled.Len() == 0 {
+		return nil, idleWait
+	}
+	return nil, p.scheduled.jobs[0].NextRun.Sub(now)
+}
+
+func (p *Pool[T]) schedule(job *Job[T]) {
+	p.mu.Lock()
+	heap.Push(p.scheduled, job)
+	p.mu.Unlock()
+	p.signal()
+}
+
+// signal wakes a waiting worker
+func (p *Pool[T]) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool[T]) attempt(ctx context.Context, job *Job[T]) {
+	err := p.cfg.Func(ctx, job.Payload)
+	job.Attempts++
+	if err == nil {
+		p.forget(ctx, job)
+		p.done(job)
+		return
+	}
+	err, permanent := isPermanent(err)
+	job.Errors = append(job.Errors, err)
+	if permanent || (p.cfg.Tries != InfiniteTries && job.Attempts >= int(p.cfg.Tries)) {
+		p.forget(ctx, job)
+		p.done(job)
+		p.deadLetter(ctx, job)
+		return
+	}
+	job.last = p.cfg.Intervals.Next(job.i, job.last)
+	if job.i < InfiniteTries {
+		job.i++
+	}
+	job.NextRun = p.now().Add(job.last)
+	_ = p.save(ctx, job)
+	p.schedule(job)
+}
+
+// jobQueue is a heap of jobs ordered by less
+type jobQueue[T any] struct {
+	jobs []*Job[T]
+	less func(a, b *Job[T]) bool
+}
+
+func runsBefore[T any](a, b *Job[T]) bool {
+	return a.NextRun.Before(b.NextRun)
+}
+
+func higherPriority[T any](a, b *Job[T]) bool {
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	return a.NextRun.Before(b.NextRun)
+}
+
+func (q *jobQueue[T]) Len() int           { return len(q.jobs) }
+func (q *jobQueue[T]) Less(i, j int) bool { return q.less(q.jobs[i], q.jobs[j]) }
+func (q *jobQueue[T]) Swap(i, j int)      { q.jobs[i], q.jobs[j] = q.jobs[j], q.jobs[i] }
+func (q *jobQueue[T]) Push(x interface{}) { q.jobs = append(q.jobs, x.(*Job[T])) }
+func (q *jobQueue[T]) Pop() interface{} {
+	last := q.jobs[len(q.jobs)-1]
+	q.jobs[len(q.jobs)-1] = nil
+	q.jobs = q.jobs[:len(q.jobs)-1]
+	return last
+}
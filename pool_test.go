@@ -0,0 +1,227 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Pool_DeadLetter(t *testing.T) {
+	errBad := errors.New("bad")
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	done := make(chan string, 10)
+	dead := make(chan backoff.Job[string], 10)
+
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			mu.Lock()
+			calls[payload]++
+			n := calls[payload]
+			mu.Unlock()
+			switch {
+			case payload == "bad":
+				return errBad
+			case payload == "permanent":
+				return backoff.Permanent(errBad)
+			case payload == "flaky" && n < 3:
+				return errBad
+			}
+			done <- payload
+			return nil
+		},
+		Intervals:  shortInterval,
+		Tries:      3,
+		Workers:    2,
+		DeadLetter: backoff.DeadLetterChan(dead),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, payload := range []string{"ok", "bad", "flaky", "permanent"} {
+		require.NoError(t, pool.Submit(ctx, payload))
+	}
+	go pool.Run(ctx)
+
+	var succeeded []string
+	for len(succeeded) < 2 {
+		succeeded = append(succeeded, <-done)
+	}
+	sort.Strings(succeeded)
+	assert.Equal(t, []string{"flaky", "ok"}, succeeded)
+
+	jobs := map[string]backoff.Job[string]{}
+	for len(jobs) < 2 {
+		job := <-dead
+		jobs[job.Payload] = job
+	}
+	assert.Equal(t, 3, jobs["bad"].Attempts)
+	assert.Equal(t, []error{errBad, errBad, errBad}, jobs["bad"].Errors)
+	assert.Equal(t, 1, jobs["permanent"].Attempts)
+	assert.Equal(t, []error{errBad}, jobs["permanent"].Errors)
+	assert.Zero(t, pool.Pending())
+}
+
+func Test_Pool_Run(t *testing.T) {
+	var dead []string
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			return assert.AnError
+		},
+		Intervals: backoff.DefaultBinaryExponential(),
+		Tries:     3,
+		DeadLetter: backoff.DeadLetterFunc[string](func(ctx context.Context, job backoff.Job[string]) {
+			dead = append(dead, job.Payload)
+		}),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, pool.Submit(ctx, "a"))
+
+	assert.Equal(t, context.DeadlineExceeded, pool.Run(ctx))
+	// the retry is pending after the first attempt
+	assert.Equal(t, 1, pool.Pending())
+	assert.Empty(t, dead)
+	assert.Equal(t, context.DeadlineExceeded, pool.Submit(ctx, "b"))
+}
+
+func Test_Pool_Priority(t *testing.T) {
+	var order []string
+	failed := false
+	finished := make(chan struct{})
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			order = append(order, payload)
+			if payload == "high" && !failed {
+				failed = true
+				return assert.AnError
+			}
+			if len(order) == 5 {
+				close(finished)
+			}
+			return nil
+		},
+		Intervals: backoff.Immediate(0).Intervals,
+		Tries:     3,
+		Workers:   1,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, payload := range []string{"low1", "low2", "low3"} {
+		require.NoError(t, pool.Submit(ctx, payload))
+	}
+	require.NoError(t, pool.SubmitWithPriority(ctx, "high", 10))
+	go pool.Run(ctx)
+	<-finished
+
+	// the retry of the high priority job preempts the low priority jobs
+	assert.Equal(t, []string{"high", "high"}, order[:2])
+	assert.ElementsMatch(t, []string{"low1", "low2", "low3"}, order[2:])
+}
+
+func Test_Pool_Overflow(t *testing.T) {
+	cases := map[string]struct {
+		overflow backoff.OverflowPolicy
+		wantErr  error
+		wantDead []string
+	}{
+		"Reject": {
+			overflow: backoff.OverflowReject,
+			wantErr:  backoff.PoolFull,
+		},
+		"Drop oldest": {
+			overflow: backoff.OverflowDropOldest,
+			wantDead: []string{"a"},
+		},
+		"Block": {
+			overflow: backoff.OverflowBlock,
+			wantErr:  context.DeadlineExceeded,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var dead []string
+			pool := backoff.NewPool(backoff.PoolConfig[string]{
+				Func: func(ctx context.Context, payload string) error {
+					return nil
+				},
+				Intervals: shortInterval,
+				Tries:     3,
+				DeadLetter: backoff.DeadLetterFunc[string](func(ctx context.Context, job backoff.Job[string]) {
+					assert.Equal(t, []error{backoff.PoolFull}, job.Errors)
+					dead = append(dead, job.Payload)
+				}),
+				MaxJobs:  2,
+				Overflow: tc.overflow,
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			require.NoError(t, pool.Submit(ctx, "a"))
+			require.NoError(t, pool.Submit(ctx, "b"))
+			err := pool.Submit(ctx, "c")
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.wantErr, err)
+			}
+			assert.Equal(t, 2, pool.Pending())
+			assert.Equal(t, tc.wantDead, dead)
+		})
+	}
+}
+
+func Test_Pool_OverflowBlock(t *testing.T) {
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			return nil
+		},
+		Intervals: shortInterval,
+		Tries:     3,
+		MaxJobs:   1,
+		Overflow:  backoff.OverflowBlock,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, pool.Submit(ctx, "a"))
+	go pool.Run(ctx)
+	// waits until "a" is done
+	require.NoError(t, pool.Submit(ctx, "b"))
+}
+
+func Test_Pool_WrappedPermanent(t *testing.T) {
+	errBad := errors.New("bad")
+	dead := make(chan backoff.Job[string], 1)
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			return fmt.Errorf("process %s: %w", payload, backoff.Permanent(errBad))
+		},
+		Intervals:  shortInterval,
+		Tries:      5,
+		DeadLetter: backoff.DeadLetterChan(dead),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, pool.Submit(ctx, "a"))
+	go pool.Run(ctx)
+
+	job := <-dead
+	assert.Equal(t, 1, job.Attempts)
+	require.Len(t, job.Errors, 1)
+	assert.True(t, errors.Is(job.Errors[0], errBad))
+	assert.EqualError(t, job.Errors[0], "process a: bad")
+}
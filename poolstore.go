@@ -0,0 +1,181 @@
+package backoff
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PoolRecord is the persisted form of a pending Pool job.
+type PoolRecord struct {
+	ID       string
+	Payload  []byte
+	Attempts int
+	Errors   []string
+	NextRun  time.Time
+	Priority int
+	// Index and Last are the position in the backoff series
+	Index int8
+	Last  time.Duration
+}
+
+// PoolStore persists the pending jobs of a Pool so they survive a restart of
+// the process. Implement it on top of BoltDB, SQLite or another database.
+// Implementations must be safe for concurrent use.
+type PoolStore interface {
+	// Save inserts or replaces the record with the same ID
+	Save(ctx context.Context, rec PoolRecord) error
+	// Delete removes the record with id. Deleting an unknown id is not an
+	// error.
+	Delete(ctx context.Context, id string) error
+	// Load returns all records
+	Load(ctx context.Context) ([]PoolRecord, error)
+}
+
+// Codec converts job payloads to and from bytes for a PoolStore.
+type Codec[T any] interface {
+	Encode(payload T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec using encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode marshals payload to JSON.
+func (JSONCodec[T]) Encode(payload T) ([]byte, error) {
+	return json.Marshal(payload)
+}
+
+// Decode unmarshals a payload from JSON.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var payload T
+	err := json.Unmarshal(data, &payload)
+	return payload, err
+}
+
+// MemoryPoolStore is a PoolStore that keeps the records in memory, for tests
+// and as a reference for other implementations.
+type MemoryPoolStore struct {
+	mu      sync.Mutex
+	records map[string]PoolRecord
+}
+
+var _ PoolStore = (*MemoryPoolStore)(nil)
+
+// NewMemoryPoolStore creates a new MemoryPoolStore.
+func NewMemoryPoolStore() *MemoryPoolStore {
+	return &MemoryPoolStore{records: make(map[string]PoolRecord)}
+}
+
+// Save stores rec.
+func (s *MemoryPoolStore) Save(ctx context.Context, rec PoolRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[rec.ID] = rec
+	return nil
+}
+
+// Delete removes the record with id.
+func (s *MemoryPoolStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	return nil
+}
+
+// Load returns all records ordered by NextRun.
+func (s *MemoryPoolStore) Load(ctx context.Context) ([]PoolRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]PoolRecord, 0, len(s.records))
+	for _, rec := range s.records {
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].NextRun.Before(records[j].NextRun)
+	})
+	return records, nil
+}
+
+// Restore adds the jobs saved in the PoolStore, for example after a restart.
+// Call it before Run. Jobs already in the Pool are skipped, so calling it
+// again is safe. When the Pool holds MaxJobs jobs, Restore stops and returns
+// PoolFull, and the jobs not restored stay in the PoolStore. It does nothing
+// without a PoolStore.
+func (p *Pool[T]) Restore(ctx context.Context) error {
+	if p.cfg.Store == nil {
+		return nil
+	}
+	records, err := p.cfg.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	jobs := make([]*Job[T], 0, len(records))
+	for _, rec := range records {
+		payload, err := p.cfg.Codec.Decode(rec.Payload)
+		if err != nil {
+			return err
+		}
+		job := &Job[T]{
+			ID:       rec.ID,
+			Payload:  payload,
+			Attempts: rec.Attempts,
+			NextRun:  rec.NextRun,
+			Priority: rec.Priority,
+			i:        rec.Index,
+			last:     rec.Last,
+		}
+		for _, e := range rec.Errors {
+			job.Errors = append(job.Errors, errors.New(e))
+		}
+		jobs = append(jobs, job)
+	}
+	defer p.signal()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, job := range jobs {
+		if p.ids[job.ID] {
+			continue
+		}
+		if p.cfg.MaxJobs > 0 && p.jobs >= p.cfg.MaxJobs {
+			return PoolFull
+		}
+		p.jobs++
+		p.add(job)
+	}
+	return nil
+}
+
+// save persists job. It does nothing without a PoolStore.
+func (p *Pool[T]) save(ctx context.Context, job *Job[T]) error {
+	if p.cfg.Store == nil {
+		return nil
+	}
+	payload, err := p.cfg.Codec.Encode(job.Payload)
+	if err != nil {
+		return err
+	}
+	rec := PoolRecord{
+		ID:       job.ID,
+		Payload:  payload,
+		Attempts: job.Attempts,
+		NextRun:  job.NextRun,
+		Priority: job.Priority,
+		Index:    job.i,
+		Last:     job.last,
+	}
+	for _, e := range job.Errors {
+		rec.Errors = append(rec.Errors, e.Error())
+	}
+	return p.cfg.Store.Save(ctx, rec)
+}
+
+// forget removes job from the PoolStore. Errors are ignored, the job is done.
+func (p *Pool[T]) forget(ctx context.Context, job *Job[T]) {
+	if p.cfg.Store != nil {
+		_ = p.cfg.Store.Delete(ctx, job.ID)
+	}
+}
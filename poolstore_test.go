@@ -0,0 +1,145 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+type order struct {
+	ID    int
+	Items []string
+}
+
+func Test_Pool_Store(t *testing.T) {
+	store := backoff.NewMemoryPoolStore()
+	cfg := backoff.PoolConfig[order]{
+		Func: func(ctx context.Context, payload order) error {
+			if payload.ID == 1 {
+				return nil
+			}
+			return assert.AnError
+		},
+		Intervals: backoff.DefaultBinaryExponential(),
+		Tries:     3,
+		Store:     store,
+		Codec:     backoff.JSONCodec[order]{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := backoff.NewPool(cfg)
+	require.NoError(t, pool.Submit(ctx, order{ID: 1}))
+	require.NoError(t, pool.Submit(ctx, order{ID: 2, Items: []string{"book"}}))
+	records, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	go pool.Run(ctx)
+	require.Eventually(t, func() bool {
+		records, err := store.Load(ctx)
+		return err == nil && len(records) == 1 && records[0].Attempts == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	// a new process restores the failed job
+	var got []order
+	cfg.Func = func(ctx context.Context, payload order) error {
+		got = append(got, payload)
+		return nil
+	}
+	restored := backoff.NewPool(cfg)
+	require.NoError(t, restored.Restore(context.Background()))
+	assert.Equal(t, 1, restored.Pending())
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go restored.Run(ctx)
+	require.Eventually(t, func() bool {
+		records, err := store.Load(ctx)
+		return err == nil && len(records) == 0
+	}, 2*time.Second, time.Millisecond)
+	cancel()
+	assert.Equal(t, []order{{ID: 2, Items: []string{"book"}}}, got)
+}
+
+func Test_Pool_Restore(t *testing.T) {
+	store := backoff.NewMemoryPoolStore()
+	ctx := context.Background()
+	payload, err := backoff.JSONCodec[string]{}.Encode("retry me")
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, backoff.PoolRecord{
+		ID:       "a",
+		Payload:  payload,
+		Attempts: 2,
+		Errors:   []string{"timeout", "refused"},
+		NextRun:  time.Now(),
+	}))
+
+	dead := make(chan backoff.Job[string], 1)
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func: func(ctx context.Context, payload string) error {
+			return backoff.Permanent(assert.AnError)
+		},
+		Intervals:  shortInterval,
+		Tries:      5,
+		DeadLetter: backoff.DeadLetterChan(dead),
+		Store:      store,
+		Codec:      backoff.JSONCodec[string]{},
+	})
+	require.NoError(t, pool.Restore(ctx))
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	go pool.Run(ctx)
+	job := <-dead
+	assert.Equal(t, "a", job.ID)
+	assert.Equal(t, "retry me", job.Payload)
+	assert.Equal(t, 3, job.Attempts)
+	require.Len(t, job.Errors, 3)
+	assert.EqualError(t, job.Errors[0], "timeout")
+	assert.EqualError(t, job.Errors[1], "refused")
+	records, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func Test_Pool_RestoreTwice(t *testing.T) {
+	store := backoff.NewMemoryPoolStore()
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, store.Save(ctx, backoff.PoolRecord{
+			ID:      id,
+			Payload: []byte(`"` + id + `"`),
+			NextRun: time.Now().Add(time.Hour),
+		}))
+	}
+
+	// the Codec defaults to JSONCodec
+	pool := backoff.NewPool(backoff.PoolConfig[string]{
+		Func:      func(ctx context.Context, payload string) error { return nil },
+		Intervals: shortInterval,
+		Tries:     3,
+		Store:     store,
+	})
+	require.NoError(t, pool.Restore(ctx))
+	require.NoError(t, pool.Restore(ctx))
+	assert.Equal(t, 3, pool.Pending())
+
+	bounded := backoff.NewPool(backoff.PoolConfig[string]{
+		Func:      func(ctx context.Context, payload string) error { return nil },
+		Intervals: shortInterval,
+		Tries:     3,
+		Store:     store,
+		MaxJobs:   2,
+	})
+	assert.Equal(t, backoff.PoolFull, bounded.Restore(ctx))
+	assert.Equal(t, 2, bounded.Pending())
+	records, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+}
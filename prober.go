@@ -0,0 +1,94 @@
+package backoff
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ProberConfig configures a Prober.
+type ProberConfig struct {
+	// Probe checks the endpoint. A nil error reports it healthy.
+	Probe Operation
+	// Intervals is the backoff series between probes while unhealthy
+	Intervals Intervals
+	// Period is the pause between probes while healthy. Defaults to
+	// DefaultProbePeriod.
+	Period time.Duration
+	// Jitter adjusts each Period by a random value between +/- Jitter *
+	// Period, like NewJitteredTicker
+	Jitter float64
+	// OnChange is called with the new state when the health changes. It is
+	// called from the goroutine of Run and delays the next probe.
+	OnChange func(healthy bool)
+}
+
+// Prober continuously probes an endpoint, with backoff while it is unhealthy
+// and at a steady jittered period while it is healthy. It is a building block
+// for client-side health checking of backends. The endpoint is unhealthy until
+// the first successful probe.
+type Prober struct {
+	cfg  ProberConfig
+	rand *rand.Rand
+
+	mu      sync.Mutex
+	healthy bool
+}
+
+// DefaultProbePeriod is the pause between probes of a healthy endpoint when
+// the Period is not set
+const DefaultProbePeriod = 10 * time.Second
+
+// NewProber creates a new Prober. Start probing with Run.
+func NewProber(cfg ProberConfig) *Prober {
+	if cfg.Period <= 0 {
+		cfg.Period = DefaultProbePeriod
+	}
+	return &Prober{
+		cfg:  cfg,
+		rand: rand.New(newLockedSource()),
+	}
+}
+
+// Healthy reports if the last probe succeeded.
+func (p *Prober) Healthy() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.healthy
+}
+
+// Run probes until ctx is done and returns the context error. The first probe
+// runs immediately.
+func (p *Prober) Run(ctx context.Context) error {
+	var i int8
+	var last time.Duration
+	for {
+		var wait time.Duration
+		if p.set(p.cfg.Probe(ctx) == nil) {
+			i, last = 0, 0
+			wait = jitterPeriod(p.rand, p.cfg.Period, p.cfg.Jitter)
+		} else {
+			last = p.cfg.Intervals.Next(i, last)
+			wait = last
+			if i < InfiniteTries {
+				i++
+			}
+		}
+		if err := Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// set records the result of a probe, notifies a change and returns healthy
+func (p *Prober) set(healthy bool) bool {
+	p.mu.Lock()
+	changed := p.healthy != healthy
+	p.healthy = healthy
+	p.mu.Unlock()
+	if changed && p.cfg.OnChange != nil {
+		p.cfg.OnChange(healthy)
+	}
+	return healthy
+}
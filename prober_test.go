@@ -0,0 +1,66 @@
+package backoff_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Prober(t *testing.T) {
+	// probes fail, fail, succeed, succeed, fail, then succeed
+	results := []error{assert.AnError, assert.AnError, nil, nil, assert.AnError}
+	var probes int32
+	changes := make(chan bool, 10)
+	prober := backoff.NewProber(backoff.ProberConfig{
+		Probe: func(ctx context.Context) error {
+			n := int(atomic.AddInt32(&probes, 1))
+			if n <= len(results) {
+				return results[n-1]
+			}
+			return nil
+		},
+		Intervals: shortInterval,
+		Period:    time.Millisecond,
+		Jitter:    0.5,
+		OnChange:  func(healthy bool) { changes <- healthy },
+	})
+	assert.False(t, prober.Healthy())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- prober.Run(ctx) }()
+
+	var got []bool
+	for len(got) < 3 {
+		got = append(got, <-changes)
+	}
+	assert.Equal(t, []bool{true, false, true}, got)
+	require.Eventually(t, prober.Healthy, time.Second, time.Millisecond)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Empty(t, changes)
+}
+
+func Test_Prober_DefaultPeriod(t *testing.T) {
+	var probes int32
+	prober := backoff.NewProber(backoff.ProberConfig{
+		Probe: func(ctx context.Context) error {
+			atomic.AddInt32(&probes, 1)
+			return nil
+		},
+		Intervals: shortInterval,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, prober.Run(ctx))
+	assert.True(t, prober.Healthy())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&probes))
+}
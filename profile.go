@@ -0,0 +1,31 @@
+package backoff
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+// WithProfilerLabels sets the pprof labels `backoff_name` to name and `attempt`
+// to the attempt number around each attempt, so CPU profiles attribute the work
+// to the retrying operation.
+func WithProfilerLabels(name string) Options {
+	return func(bo *Backoff) {
+		bo.profileName = name
+	}
+}
+
+// labeled wraps fn to run with the pprof labels of the attempt
+func (b *Backoff) labeled(fn Operation) Operation {
+	return func(ctx context.Context) (err error) {
+		attempt := 0
+		if a, ok := ScheduleFromContext(ctx); ok {
+			attempt = a.Number
+		}
+		labels := pprof.Labels("backoff_name", b.profileName, "attempt", strconv.Itoa(attempt))
+		pprof.Do(ctx, labels, func(ctx context.Context) {
+			err = fn(ctx)
+		})
+		return err
+	}
+}
@@ -0,0 +1,29 @@
+package backoff_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithProfilerLabels(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var attempts []string
+	bo := backoff.NewBackoff(shortInterval, backoff.WithProfilerLabels("fetch-orders"))
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool {
+		name, _ := pprof.Label(ctx, "backoff_name")
+		assert.Equal(t, "fetch-orders", name)
+		attempt, _ := pprof.Label(ctx, "attempt")
+		attempts = append(attempts, attempt)
+		return len(attempts) == 2
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, attempts)
+}
@@ -0,0 +1,47 @@
+package backoff
+
+import (
+	"strings"
+	"time"
+)
+
+// RampUp is the inverse of a backoff series: it produces gradually shortening
+// delays, for example to slowly re-admit traffic after an incident
+// (slow-start). It reverses the first `steps` intervals of another series, so a
+// RampUp of DefaultBinaryExponential with 4 steps produces:
+//
+//	4s, 2s, 1s, 0.5s, 0, 0, ...
+//
+// Once the ramp is complete the delay is zero.
+type RampUp struct {
+	delays []time.Duration
+}
+
+var _ Intervals = RampUp{}
+
+// NewRampUp creates a RampUp from the first `steps` intervals of iv.
+func NewRampUp(iv Intervals, steps int8) RampUp {
+	schedule := Schedule(iv, int(steps))
+	delays := make([]time.Duration, len(schedule))
+	for i, e := range schedule {
+		delays[len(schedule)-1-i] = e.Interval
+	}
+	return RampUp{delays: delays}
+}
+
+// Next returns the delay for step `i`. `last` is not used.
+func (r RampUp) Next(i int8, last time.Duration) time.Duration {
+	if i < 0 || int(i) >= len(r.delays) {
+		return 0
+	}
+	return r.delays[i]
+}
+
+// String describes the series, for example "ramp up 4s, 2s, 1s, 500ms".
+func (r RampUp) String() string {
+	ds := make([]string, len(r.delays))
+	for i, d := range r.delays {
+		ds[i] = d.String()
+	}
+	return "ramp up " + strings.Join(ds, ", ")
+}
@@ -0,0 +1,27 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_RampUp(t *testing.T) {
+	r := backoff.NewRampUp(backoff.DefaultBinaryExponential(), 4)
+
+	var got []time.Duration
+	for i := int8(0); i < 6; i++ {
+		got = append(got, r.Next(i, 0))
+	}
+	assert.Equal(t, []time.Duration{
+		4 * time.Second,
+		2 * time.Second,
+		1 * time.Second,
+		500 * time.Millisecond,
+		0,
+		0,
+	}, got)
+}
@@ -0,0 +1,35 @@
+package backoff
+
+import "context"
+
+// Reauthorizer refreshes credentials when an attempt fails because they
+// expired. See WithReauthorizer.
+type Reauthorizer struct {
+	// Class is the Class the classifier assigns to expired credential errors
+	Class Class
+	// Refresh refreshes the credentials
+	Refresh Operation
+	// Backoff and Tries retry Refresh with its own policy. With a nil Backoff
+	// Refresh is called once.
+	Backoff *Backoff
+	Tries   int8
+}
+
+// WithReauthorizer calls r.Refresh when an attempt fails with an error of
+// r.Class and then makes the next attempt right away, without a pause and
+// without consuming a try. If the attempt after a refresh fails with r.Class
+// again, it is handled like any other failure so a broken refresh cannot loop
+// forever. If the refresh fails, the loop stops with RetryAborted and the
+// refresh error.
+func WithReauthorizer(r Reauthorizer) Options {
+	return func(bo *Backoff) {
+		bo.reauth = &r
+	}
+}
+
+func (r *Reauthorizer) refresh(ctx context.Context) error {
+	if r.Backoff == nil {
+		return r.Refresh(ctx)
+	}
+	return r.Backoff.TryErr(ctx, r.Tries, r.Refresh)
+}
@@ -0,0 +1,147 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithReauthorizer(t *testing.T) {
+	expired := errors.New("token expired")
+	refreshErr := errors.New("identity provider down")
+
+	cases := map[string]struct {
+		errs        []error
+		refreshErrs []error
+		wantErr     error
+		wantCalls   int
+		wantRefresh int
+	}{
+		"Refresh without consuming a try": {
+			errs:        []error{assert.AnError, expired, assert.AnError, nil},
+			wantCalls:   4,
+			wantRefresh: 1,
+		},
+		"Refresh is retried with its own policy": {
+			errs:        []error{expired, nil},
+			refreshErrs: []error{refreshErr, refreshErr},
+			wantCalls:   2,
+			wantRefresh: 3,
+		},
+		"Refresh fails": {
+			errs:        []error{expired, nil},
+			refreshErrs: []error{refreshErr, refreshErr, refreshErr},
+			wantErr:     backoff.RetryAborted,
+			wantCalls:   1,
+			wantRefresh: 3,
+		},
+		"Expired again after a refresh consumes a try": {
+			errs:        []error{expired, expired, expired, expired, expired, expired},
+			wantErr:     backoff.AllTriesFailed,
+			wantCalls:   6,
+			wantRefresh: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			refreshes := 0
+			bo := backoff.NewBackoff(shortInterval,
+				backoff.WithClassifier(func(err error) backoff.Class {
+					if errors.Is(err, expired) {
+						return "auth"
+					}
+					return ""
+				}),
+				backoff.WithReauthorizer(backoff.Reauthorizer{
+					Class: "auth",
+					Refresh: func(ctx context.Context) error {
+						refreshes++
+						if refreshes <= len(tc.refreshErrs) {
+							return tc.refreshErrs[refreshes-1]
+						}
+						return nil
+					},
+					Backoff: backoff.NewBackoff(shortInterval),
+					Tries:   3,
+				}),
+			)
+			calls := 0
+			err := bo.TryErr(ctx, 3, func(ctx context.Context) error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantRefresh, refreshes)
+		})
+	}
+}
+
+func Test_WithReauthorizer_Recorder(t *testing.T) {
+	expired := errors.New("token expired")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rec := backoff.NewRecorder(10)
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithRecorder(rec),
+		backoff.WithClassifier(func(err error) backoff.Class {
+			if errors.Is(err, expired) {
+				return "auth"
+			}
+			return ""
+		}),
+		backoff.WithReauthorizer(backoff.Reauthorizer{
+			Class:   "auth",
+			Refresh: func(ctx context.Context) error { return nil },
+			Backoff: backoff.NewBackoff(shortInterval),
+			Tries:   1,
+		}),
+	)
+	err := bo.TryErr(ctx, 3, failWith(expired))
+	assert.NoError(t, err)
+
+	records := rec.Snapshot()
+	if assert.Len(t, records, 2) {
+		assert.ErrorIs(t, records[0].Err, expired)
+		assert.Equal(t, time.Duration(0), records[0].Wait)
+		assert.NoError(t, records[1].Err)
+	}
+}
+
+func Test_WithReauthorizer_CollectErrors(t *testing.T) {
+	expired := errors.New("token expired")
+	refreshErr := errors.New("identity provider down")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval,
+		backoff.WithCollectErrors(),
+		backoff.WithClassifier(func(err error) backoff.Class {
+			if errors.Is(err, expired) {
+				return "auth"
+			}
+			return ""
+		}),
+		backoff.WithReauthorizer(backoff.Reauthorizer{
+			Class:   "auth",
+			Refresh: func(ctx context.Context) error { return refreshErr },
+			Backoff: backoff.NewBackoff(shortInterval),
+			Tries:   1,
+		}),
+	)
+	err := bo.TryErr(ctx, 3, failWith(expired))
+
+	assert.ErrorIs(t, err, backoff.RetryAborted)
+	assert.ErrorIs(t, err, expired)
+	assert.ErrorIs(t, err, refreshErr)
+}
@@ -0,0 +1,80 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// Record is a single attempt captured by a Recorder.
+type Record struct {
+	// Start is when the attempt started
+	Start time.Time
+	// Attempt is the attempt number of the Try call starting from 1
+	Attempt int
+	// Err is the error of the attempt, nil if it succeeded
+	Err error
+	// Duration is how long the attempt took
+	Duration time.Duration
+	// Wait is the backoff pause after the attempt, zero if there was none
+	Wait time.Duration
+}
+
+// Recorder captures the retry history of Backoff instances in a bounded ring
+// buffer so it can be retrieved later, for example to attach the exact retry
+// history to a bug report. Once full, the oldest records are overwritten. A
+// Recorder is safe for concurrent use and can be shared.
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewRecorder creates a new Recorder that keeps the last `size` records.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+	return &Recorder{
+		records: make([]Record, size),
+	}
+}
+
+// WithRecorder captures every attempt and computed wait into the Recorder. It
+// adds RecordMiddleware(r) to the middleware chain.
+func WithRecorder(r *Recorder) Options {
+	return WithMiddleware(RecordMiddleware(r))
+}
+
+// Snapshot returns a copy of the records, oldest first.
+func (r *Recorder) Snapshot() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Record(nil), r.records[:r.next]...)
+	}
+	snapshot := make([]Record, 0, len(r.records))
+	snapshot = append(snapshot, r.records[r.next:]...)
+	return append(snapshot, r.records[:r.next]...)
+}
+
+// record adds a record. It is safe to call on a nil Recorder.
+func (r *Recorder) record(start time.Time, attempt int, err error, d, wait time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[r.next] = Record{
+		Start:    start,
+		Attempt:  attempt,
+		Err:      err,
+		Duration: d,
+		Wait:     wait,
+	}
+	r.next++
+	if r.next == len(r.records) {
+		r.next = 0
+		r.full = true
+	}
+}
@@ -0,0 +1,60 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithRecorder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rec := backoff.NewRecorder(10)
+	bo := backoff.NewBackoff(shortInterval, backoff.WithRecorder(rec))
+	err := bo.TryErr(ctx, 5, failWith(errors.New("e1"), errors.New("e2")))
+	require.NoError(t, err)
+
+	records := rec.Snapshot()
+	require.Len(t, records, 3)
+	for i, want := range []struct {
+		err  string
+		wait time.Duration
+	}{
+		{err: "e1", wait: 1 * time.Millisecond},
+		{err: "e2", wait: 2 * time.Millisecond},
+		{err: "", wait: 0},
+	} {
+		assert.Equal(t, i+1, records[i].Attempt)
+		assert.Equal(t, want.wait, records[i].Wait)
+		if want.err == "" {
+			assert.NoError(t, records[i].Err)
+		} else {
+			assert.EqualError(t, records[i].Err, want.err)
+		}
+		assert.False(t, records[i].Start.IsZero())
+	}
+}
+
+func Test_Recorder_IsBounded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rec := backoff.NewRecorder(2)
+	bo := backoff.NewBackoff(shortInterval, backoff.WithRecorder(rec))
+	err := bo.Try(ctx, 4, func(ctx context.Context) bool { return false })
+	assert.Equal(t, backoff.AllTriesFailed, err)
+
+	records := rec.Snapshot()
+	require.Len(t, records, 2)
+	assert.Equal(t, 3, records[0].Attempt)
+	assert.Equal(t, 4, records[1].Attempt)
+	assert.Equal(t, backoff.AttemptFailed, records[1].Err)
+	assert.Equal(t, time.Duration(0), records[1].Wait)
+}
@@ -0,0 +1,40 @@
+package backoff
+
+import "errors"
+
+// RepeatedError indicates that the same error repeated the number of times set
+// with WithGiveUpOnRepeatedError so no further tries were made
+const RepeatedError = Error("same error repeated")
+
+// WithGiveUpOnRepeatedError stops the loop once n consecutive attempts failed
+// with the same error, on the theory that identical deterministic failures do
+// not heal with time. Errors are the same if either matches the other with
+// errors.Is or they have the same message. Try returns RepeatedError. A
+// Completable that returns false always fails with AttemptFailed, so this is
+// mostly useful with TryErr.
+func WithGiveUpOnRepeatedError(n int) Options {
+	return func(bo *Backoff) {
+		bo.repeatLimit = n
+	}
+}
+
+// repeats counts consecutive identical errors
+type repeats struct {
+	last  error
+	count int
+}
+
+// seen records err and returns how many consecutive times it occurred
+func (r *repeats) seen(err error) int {
+	if r.last != nil && sameError(err, r.last) {
+		r.count++
+	} else {
+		r.count = 1
+	}
+	r.last = err
+	return r.count
+}
+
+func sameError(err, target error) bool {
+	return errors.Is(err, target) || errors.Is(target, err) || err.Error() == target.Error()
+}
@@ -0,0 +1,69 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_GiveUpOnRepeatedError(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	cases := map[string]struct {
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		"Same error repeats": {
+			errs:      []error{errA, errA, errA, errA, errA},
+			wantErr:   backoff.RepeatedError,
+			wantCalls: 3,
+		},
+		"Wrapped error repeats": {
+			errs:      []error{errA, fmt.Errorf("wrapped: %w", errA), errA},
+			wantErr:   backoff.RepeatedError,
+			wantCalls: 3,
+		},
+		"Same message repeats": {
+			errs:      []error{errors.New("x"), errors.New("x"), errors.New("x")},
+			wantErr:   backoff.RepeatedError,
+			wantCalls: 3,
+		},
+		"Alternating errors": {
+			errs:      []error{errA, errB, errA, errB, errA},
+			wantErr:   backoff.AllTriesFailed,
+			wantCalls: 5,
+		},
+		"Success before the limit": {
+			errs:      []error{errA, errA},
+			wantCalls: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			bo := backoff.NewBackoff(shortInterval, backoff.WithGiveUpOnRepeatedError(3))
+			calls := 0
+			err := bo.TryErr(context.Background(), 5, func(ctx context.Context) error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			})
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
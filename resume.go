@@ -0,0 +1,41 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// TryFrom is like Try but resumes the series where an earlier loop stopped,
+// for example from persisted state: i is the number of tries already used and
+// last the last backoff pause.
+//
+// The state is clamped so a corrupted value cannot break the loop. A negative
+// i starts the series from the beginning, a negative last is treated as zero
+// and an i that uses up all tries leaves a single attempt.
+func (b *Backoff) TryFrom(ctx context.Context, tries int8, fn Completable, i int8, last time.Duration, options ...Options) error {
+	i, last = clampResume(tries, i, last)
+	return b.with(options).try(ctx, tries, fn, i, last)
+}
+
+// TryErrFrom is like TryErr but resumes the series like TryFrom.
+func (b *Backoff) TryErrFrom(ctx context.Context, tries int8, fn Operation, i int8, last time.Duration, options ...Options) error {
+	i, last = clampResume(tries, i, last)
+	return b.with(options).tryErr(ctx, tries, fn, i, last)
+}
+
+// clampResume makes the resume state i and last valid for tries
+func clampResume(tries, i int8, last time.Duration) (int8, time.Duration) {
+	if i <= 0 {
+		return 0, 0
+	}
+	if last < 0 {
+		last = 0
+	}
+	if tries != InfiniteTries && i >= tries {
+		i = tries - 1
+		if i < 0 {
+			i = 0
+		}
+	}
+	return i, last
+}
@@ -0,0 +1,75 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TryFrom(t *testing.T) {
+	cases := map[string]struct {
+		i         int8
+		last      time.Duration
+		wantCalls int
+		wantWaits []time.Duration
+	}{
+		"Start": {
+			wantCalls: 5,
+			wantWaits: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		"Resume mid series": {
+			i:         2,
+			last:      time.Second,
+			wantCalls: 3,
+			wantWaits: []time.Duration{2 * time.Second, 4 * time.Second},
+		},
+		"Negative index starts over": {
+			i:         -3,
+			last:      time.Minute,
+			wantCalls: 5,
+			wantWaits: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		"Negative last": {
+			i:         2,
+			last:      -time.Second,
+			wantCalls: 3,
+			wantWaits: []time.Duration{2 * time.Second, 4 * time.Second},
+		},
+		"Index beyond tries leaves one attempt": {
+			i:         9,
+			last:      time.Second,
+			wantCalls: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var waits []time.Duration
+			b := NewBackoff(DefaultBinaryExponential(), withAfterFunc(func(d time.Duration) <-chan time.Time {
+				waits = append(waits, d)
+				return immediateAfterFunc(d)
+			}))
+			calls := 0
+			err := b.TryFrom(context.Background(), 5, func(ctx context.Context) bool {
+				calls++
+				return false
+			}, tc.i, tc.last)
+			assert.Equal(t, AllTriesFailed, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantWaits, waits)
+
+			waits = nil
+			calls = 0
+			err = b.TryErrFrom(context.Background(), 5, func(ctx context.Context) error {
+				calls++
+				return AttemptFailed
+			}, tc.i, tc.last)
+			assert.ErrorIs(t, err, AllTriesFailed)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantWaits, waits)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package backoff
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RetryError is the error returned by TryErr when it gives up. It matches its
+// Reason and Err with errors.Is and errors.As.
+//
+// RetryError implements json.Marshaler so services that log JSON get the retry
+// diagnostics in their error field.
+type RetryError struct {
+	// Reason is why the loop stopped, like AllTriesFailed or
+	// BackoffContextTimeoutExceeded
+	Reason error
+	// Err is the error of the last attempt, or the errors of all attempts with
+	// WithCollectErrors
+	Err error
+	// Policy describes the Intervals series, see Describe
+	Policy string
+	// Attempts is the number of attempts made
+	Attempts int
+	// Pauses is the number of backoff pauses. There is no pause after the
+	// last attempt so with AllTriesFailed it is one less than Attempts.
+	Pauses int
+	// Waited is the sum of the backoff pauses between the attempts
+	Waited time.Duration
+	// Start and End are when the loop started and gave up
+	Start time.Time
+	End   time.Time
+}
+
+func (e *RetryError) Error() string {
+	return e.Reason.Error() + ": " + e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() []error {
+	return []error{e.Reason, e.Err}
+}
+
+type retryErrorJSON struct {
+	Reason        string    `json:"reason"`
+	Error         string    `json:"error"`
+	Policy        string    `json:"policy"`
+	Attempts      int       `json:"attempts"`
+	Pauses        int       `json:"pauses"`
+	Waited        string    `json:"waited"`
+	WaitedSeconds float64   `json:"waited_seconds"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+}
+
+// MarshalJSON encodes the error with the same duration fields as DumpSchedule
+// with FormatJSON.
+func (e *RetryError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(retryErrorJSON{
+		Reason:        e.Reason.Error(),
+		Error:         e.Err.Error(),
+		Policy:        e.Policy,
+		Attempts:      e.Attempts,
+		Pauses:        e.Pauses,
+		Waited:        e.Waited.String(),
+		WaitedSeconds: e.Waited.Seconds(),
+		Start:         e.Start,
+		End:           e.End,
+	})
+}
@@ -0,0 +1,47 @@
+package backoff_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_RetryError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	lastErr := errors.New("connection refused")
+	bo := backoff.NewBackoff(shortInterval)
+
+	err := bo.TryErr(ctx, 3, failWith(lastErr, lastErr, lastErr))
+
+	var retryErr *backoff.RetryError
+	require.ErrorAs(t, err, &retryErr)
+	assert.ErrorIs(t, err, backoff.AllTriesFailed)
+	assert.ErrorIs(t, err, lastErr)
+	assert.Equal(t, "all tries failed: connection refused", err.Error())
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.Equal(t, 2, retryErr.Pauses)
+	assert.Equal(t, 3*time.Millisecond, retryErr.Waited)
+	assert.False(t, retryErr.End.Before(retryErr.Start))
+
+	data, err := json.Marshal(err)
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "all tries failed", got["reason"])
+	assert.Equal(t, "connection refused", got["error"])
+	assert.Equal(t, "exponential x2 from 1ms capped 20ms", got["policy"])
+	assert.Equal(t, float64(3), got["attempts"])
+	assert.Equal(t, float64(2), got["pauses"])
+	assert.Equal(t, "3ms", got["waited"])
+	assert.Equal(t, 0.003, got["waited_seconds"])
+	assert.Contains(t, got, "start")
+	assert.Contains(t, got, "end")
+}
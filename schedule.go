@@ -0,0 +1,149 @@
+package backoff
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Format is an output format.
+type Format int
+
+const (
+	// FormatText is a human readable format
+	FormatText Format = iota
+	// FormatCSV is comma separated values with a header row
+	FormatCSV
+	// FormatJSON is JSON
+	FormatJSON
+)
+
+// ParseFormat parses "text", "csv" or "json" into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "text":
+		return FormatText, nil
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return FormatText, fmt.Errorf("unknown format %q", s)
+}
+
+// ScheduleEntry is a single interval of a series.
+type ScheduleEntry struct {
+	// Iteration is the `i` passed to Intervals.Next
+	Iteration int
+	// Interval is the backoff pause after attempt Iteration+1
+	Interval time.Duration
+	// Cumulative is the sum of all intervals up to and including this one
+	Cumulative time.Duration
+}
+
+// Schedule returns the first n intervals of iv the way Try would compute them.
+func Schedule(iv Intervals, n int) []ScheduleEntry {
+	entries := make([]ScheduleEntry, 0, n)
+	var last, cumulative time.Duration
+	for i := 0; i < n; i++ {
+		iteration := i
+		if iteration > InfiniteTries {
+			iteration = InfiniteTries
+		}
+		last = iv.Next(int8(iteration), last)
+		cumulative += last
+		entries = append(entries, ScheduleEntry{
+			Iteration:  i,
+			Interval:   last,
+			Cumulative: cumulative,
+		})
+	}
+	return entries
+}
+
+// DumpSchedule writes the first n intervals of iv including the cumulative time
+// to w in the given format. This is useful for design reviews and runbooks.
+func DumpSchedule(iv Intervals, n int, w io.Writer, format Format) error {
+	entries := Schedule(iv, n)
+	switch format {
+	case FormatCSV:
+		return dumpCSV(entries, w)
+	case FormatJSON:
+		return dumpJSON(entries, w)
+	default:
+		return dumpText(entries, w)
+	}
+}
+
+func dumpText(entries []ScheduleEntry, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ITERATION\tINTERVAL\tCUMULATIVE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", e.Iteration, e.Interval, e.Cumulative)
+	}
+	return tw.Flush()
+}
+
+func dumpCSV(entries []ScheduleEntry, w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"iteration", "interval", "cumulative", "interval_seconds", "cumulative_seconds"})
+	for _, e := range entries {
+		_ = cw.Write([]string{
+			strconv.Itoa(e.Iteration),
+			e.Interval.String(),
+			e.Cumulative.String(),
+			strconv.FormatFloat(e.Interval.Seconds(), 'f', -1, 64),
+			strconv.FormatFloat(e.Cumulative.Seconds(), 'f', -1, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type scheduleEntryJSON struct {
+	Iteration         int     `json:"iteration"`
+	Interval          string  `json:"interval"`
+	Cumulative        string  `json:"cumulative"`
+	IntervalSeconds   float64 `json:"interval_seconds"`
+	CumulativeSeconds float64 `json:"cumulative_seconds"`
+}
+
+func dumpJSON(entries []ScheduleEntry, w io.Writer) error {
+	out := make([]scheduleEntryJSON, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, scheduleEntryJSON{
+			Iteration:         e.Iteration,
+			Interval:          e.Interval.String(),
+			Cumulative:        e.Cumulative.String(),
+			IntervalSeconds:   e.Interval.Seconds(),
+			CumulativeSeconds: e.Cumulative.Seconds(),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// Buckets returns histogram bucket upper bounds in seconds at the first n
+// intervals of iv, sorted and without duplicates. Use them for wait duration
+// histograms, like the Buckets of a Prometheus HistogramOpts, so the
+// resolution matches the intervals the policy actually produces.
+func Buckets(iv Intervals, n int) []float64 {
+	var buckets []float64
+	for _, e := range Schedule(iv, n) {
+		buckets = append(buckets, e.Interval.Seconds())
+	}
+	sort.Float64s(buckets)
+	out := buckets[:0]
+	for i, b := range buckets {
+		if i == 0 || b != buckets[i-1] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package backoff_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Schedule(t *testing.T) {
+	got := backoff.Schedule(backoff.DefaultBinaryExponential(), 4)
+	want := []backoff.ScheduleEntry{
+		{Iteration: 0, Interval: 500 * time.Millisecond, Cumulative: 500 * time.Millisecond},
+		{Iteration: 1, Interval: 1 * time.Second, Cumulative: 1500 * time.Millisecond},
+		{Iteration: 2, Interval: 2 * time.Second, Cumulative: 3500 * time.Millisecond},
+		{Iteration: 3, Interval: 4 * time.Second, Cumulative: 7500 * time.Millisecond},
+	}
+	assert.Equal(t, want, got)
+}
+
+func Test_DumpSchedule(t *testing.T) {
+	cases := map[string]struct {
+		format backoff.Format
+		want   string
+	}{
+		"text": {
+			format: backoff.FormatText,
+			want: "ITERATION  INTERVAL  CUMULATIVE\n" +
+				"0          500ms     500ms\n" +
+				"1          1s        1.5s\n",
+		},
+		"csv": {
+			format: backoff.FormatCSV,
+			want: "iteration,interval,cumulative,interval_seconds,cumulative_seconds\n" +
+				"0,500ms,500ms,0.5,0.5\n" +
+				"1,1s,1.5s,1,1.5\n",
+		},
+		"json": {
+			format: backoff.FormatJSON,
+			want: `[
+  {
+    "iteration": 0,
+    "interval": "500ms",
+    "cumulative": "500ms",
+    "interval_seconds": 0.5,
+    "cumulative_seconds": 0.5
+  },
+  {
+    "iteration": 1,
+    "interval": "1s",
+    "cumulative": "1.5s",
+    "interval_seconds": 1,
+    "cumulative_seconds": 1.5
+  }
+]
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			var buf bytes.Buffer
+			err := backoff.DumpSchedule(backoff.DefaultBinaryExponential(), 2, &buf, tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	f, err := backoff.ParseFormat("csv")
+	assert.NoError(t, err)
+	assert.Equal(t, backoff.FormatCSV, f)
+
+	_, err = backoff.ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func Test_Buckets(t *testing.T) {
+	cases := map[string]struct {
+		iv   backoff.Intervals
+		n    int
+		want []float64
+	}{
+		"DefaultBinaryExponential": {
+			iv:   backoff.DefaultBinaryExponential(),
+			n:    10,
+			want: []float64{0.5, 1, 2, 4, 8, 16, 20},
+		},
+		"RampUp is sorted": {
+			iv:   backoff.NewRampUp(backoff.DefaultBinaryExponential(), 3),
+			n:    4,
+			want: []float64{0, 0.5, 1, 2},
+		},
+		"No intervals": {
+			iv:   backoff.DefaultBinaryExponential(),
+			n:    0,
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.want, backoff.Buckets(tc.iv, tc.n))
+		})
+	}
+}
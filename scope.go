@@ -0,0 +1,67 @@
+package backoff
+
+import "context"
+
+// WithAttemptScope calls setup once before the first attempt of each Try call
+// and the returned cleanup, if not nil, when the loop ends. This avoids
+// repeating expensive setup, like opening a connection or fetching a token,
+// for every attempt. After failures of the given classes, see WithClassifier,
+// the scope is cleaned up and setup runs again before the next attempt, for
+// example to re-authenticate after a 401.
+//
+// If setup fails, the attempt fails with its error without calling the
+// function, and setup runs again before the next attempt.
+func WithAttemptScope(setup func(ctx context.Context) (cleanup func(), err error), resetOn ...Class) Options {
+	return func(bo *Backoff) {
+		bo.scopeSetup = setup
+		bo.scopeReset = make(map[Class]bool, len(resetOn))
+		for _, class := range resetOn {
+			bo.scopeReset[class] = true
+		}
+	}
+}
+
+// scope holds the state of WithAttemptScope for a single Try call. A nil scope
+// does nothing.
+type scope struct {
+	setup   func(ctx context.Context) (cleanup func(), err error)
+	ready   bool
+	cleanup func()
+}
+
+func (b *Backoff) newScope() *scope {
+	if b.scopeSetup == nil {
+		return nil
+	}
+	return &scope{setup: b.scopeSetup}
+}
+
+// wrap runs the setup if needed before fn
+func (s *scope) wrap(fn Operation) Operation {
+	if s == nil {
+		return fn
+	}
+	return func(ctx context.Context) error {
+		if !s.ready {
+			cleanup, err := s.setup(ctx)
+			if err != nil {
+				return err
+			}
+			s.cleanup = cleanup
+			s.ready = true
+		}
+		return fn(ctx)
+	}
+}
+
+// close cleans up the scope so the next attempt runs the setup again
+func (s *scope) close() {
+	if s == nil || !s.ready {
+		return
+	}
+	if s.cleanup != nil {
+		s.cleanup()
+	}
+	s.cleanup = nil
+	s.ready = false
+}
@@ -0,0 +1,83 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithAttemptScope(t *testing.T) {
+	unauthorized := errors.New("401 unauthorized")
+	setupErr := errors.New("token endpoint down")
+
+	cases := map[string]struct {
+		setupErrs  []error
+		errs       []error
+		wantEvents []string
+	}{
+		"Setup once": {
+			errs: []error{assert.AnError, assert.AnError, nil},
+			wantEvents: []string{
+				"setup:1", "attempt:1", "attempt:1", "attempt:1", "cleanup:1",
+			},
+		},
+		"Setup again after a reset class": {
+			errs: []error{unauthorized, assert.AnError, nil},
+			wantEvents: []string{
+				"setup:1", "attempt:1", "cleanup:1", "setup:2", "attempt:2", "attempt:2", "cleanup:2",
+			},
+		},
+		"Setup failure is retried": {
+			setupErrs: []error{setupErr},
+			errs:      []error{nil},
+			wantEvents: []string{
+				"setup:1", "setup:2", "attempt:2", "cleanup:2",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			var events []string
+			token := 0
+			setups := 0
+			bo := backoff.NewBackoff(shortInterval,
+				backoff.WithClassifier(func(err error) backoff.Class {
+					if errors.Is(err, unauthorized) {
+						return "auth"
+					}
+					return ""
+				}),
+				backoff.WithAttemptScope(func(ctx context.Context) (func(), error) {
+					token++
+					events = append(events, fmt.Sprintf("setup:%d", token))
+					setups++
+					if setups <= len(tc.setupErrs) {
+						return nil, tc.setupErrs[setups-1]
+					}
+					current := token
+					return func() { events = append(events, fmt.Sprintf("cleanup:%d", current)) }, nil
+				}, "auth"),
+			)
+			calls := 0
+			err := bo.TryErr(ctx, 5, func(ctx context.Context) error {
+				events = append(events, fmt.Sprintf("attempt:%d", token))
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantEvents, events)
+		})
+	}
+}
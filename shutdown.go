@@ -0,0 +1,179 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ShutDown is returned by Try and TryErr when the Backoff or the package was
+// shut down with Shutdown or ShutdownAll
+const ShutDown = Error("backoff shut down")
+
+// shutdown coordinates the shutdown of the Try calls of a Backoff, or of all
+// Try calls for the package wide instance. Attempts register without a lock so
+// unrelated retry loops do not contend.
+type shutdown struct {
+	state     atomic.Int64 // attempts in flight, plus shutdownClosed
+	closeOnce sync.Once
+	idleOnce  sync.Once
+	draining  chan struct{} // closed by Shutdown
+	idle      chan struct{} // closed once shut down without in-flight attempts
+
+	abortCtx context.Context // cancelled when the grace period ends
+	abort    context.CancelFunc
+}
+
+// shutdownClosed is set in shutdown.state by Shutdown
+const shutdownClosed = 1 << 62
+
+func newShutdown() *shutdown {
+	abortCtx, abort := context.WithCancel(context.Background())
+	return &shutdown{
+		draining: make(chan struct{}),
+		idle:     make(chan struct{}),
+		abortCtx: abortCtx,
+		abort:    abort,
+	}
+}
+
+// allShutdown is shut down by ShutdownAll and replaced by ResetShutdownAll.
+// Each Try call uses the instance current when it starts.
+var allShutdown atomic.Pointer[shutdown]
+
+func init() {
+	allShutdown.Store(newShutdown())
+}
+
+// Shutdown stops the Try calls of b from starting new attempts, wakes the
+// calls that are in a backoff pause and waits for the attempts in flight to
+// finish. ctx bounds the grace period: when it is done, the contexts of the
+// attempts still in flight are cancelled and Shutdown returns the context
+// error. Try calls that were stopped return ShutDown, and so do later calls.
+//
+// The Backoff copies made for per call options share the shutdown.
+func (b *Backoff) Shutdown(ctx context.Context) error {
+	return b.shutdown.shutdown(ctx)
+}
+
+// ShutdownAll is like Shutdown for the Try calls of every Backoff, for
+// services that coordinate their shutdown in one place.
+func ShutdownAll(ctx context.Context) error {
+	return allShutdown.Load().shutdown(ctx)
+}
+
+// ResetShutdownAll undoes ShutdownAll for the Try calls started afterwards, for
+// example between tests or when a service restarts its components in
+// process. Calls already stopped by ShutdownAll are not resumed.
+func ResetShutdownAll() {
+	allShutdown.Store(newShutdown())
+}
+
+func (s *shutdown) shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.closeOnce.Do(func() {
+		close(s.draining)
+		for {
+			v := s.state.Load()
+			if s.state.CompareAndSwap(v, v|shutdownClosed) {
+				if v == 0 {
+					s.idleOnce.Do(func() { close(s.idle) })
+				}
+				return
+			}
+		}
+	})
+	select {
+	case <-s.idle:
+		return nil
+	case <-ctx.Done():
+		s.abort()
+		return ctx.Err()
+	}
+}
+
+// enter registers an attempt. It returns false after a shutdown. It is safe to
+// call on a nil shutdown.
+func (s *shutdown) enter() bool {
+	if s == nil {
+		return true
+	}
+	for {
+		v := s.state.Load()
+		if v&shutdownClosed != 0 {
+			return false
+		}
+		if s.state.CompareAndSwap(v, v+1) {
+			return true
+		}
+	}
+}
+
+// leave unregisters an attempt
+func (s *shutdown) leave() {
+	if s == nil {
+		return
+	}
+	if s.state.Add(-1) == shutdownClosed {
+		s.idleOnce.Do(func() { close(s.idle) })
+	}
+}
+
+// drained returns the channel closed by Shutdown, nil for a nil shutdown
+func (s *shutdown) drained() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.draining
+}
+
+// isClosed reports if Shutdown was called
+func (s *shutdown) isClosed() bool {
+	select {
+	case <-s.drained():
+		return true
+	default:
+		return false
+	}
+}
+
+// enterAttempt registers an attempt with the Backoff and all, the package
+// wide shutdown of the Try call
+func (b *Backoff) enterAttempt(all *shutdown) bool {
+	if !all.enter() {
+		return false
+	}
+	if !b.shutdown.enter() {
+		all.leave()
+		return false
+	}
+	return true
+}
+
+func (b *Backoff) leaveAttempt(all *shutdown) {
+	b.shutdown.leave()
+	all.leave()
+}
+
+// shuttingDown reports if the Backoff or the package was shut down
+func (b *Backoff) shuttingDown(all *shutdown) bool {
+	return all.isClosed() || b.shutdown.isClosed()
+}
+
+// withShutdown returns a copy of ctx that is cancelled when a grace period of
+// a shutdown ends
+func (b *Backoff) withShutdown(ctx context.Context, all *shutdown) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	stopAll := context.AfterFunc(all.abortCtx, cancel)
+	stop := func() bool { return false }
+	if b.shutdown != nil {
+		stop = context.AfterFunc(b.shutdown.abortCtx, cancel)
+	}
+	return ctx, func() {
+		stopAll()
+		stop()
+		cancel()
+	}
+}
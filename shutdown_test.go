@@ -0,0 +1,142 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var minuteInterval = Exponential{
+	Base:    time.Minute,
+	Unit:    time.Minute,
+	Initial: time.Minute,
+	Max:     time.Minute,
+}
+
+func Test_Shutdown_WakesPauses(t *testing.T) {
+	bo := NewBackoff(minuteInterval)
+	attempted := make(chan struct{}, 1)
+	result := make(chan error)
+	go func() {
+		result <- bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+			attempted <- struct{}{}
+			return false
+		})
+	}()
+	<-attempted
+
+	require.NoError(t, bo.Shutdown(context.Background()))
+	select {
+	case err := <-result:
+		assert.Equal(t, ShutDown, err)
+	case <-time.After(time.Second):
+		t.Fatal("Try did not return after Shutdown")
+	}
+
+	calls := 0
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		calls++
+		return true
+	}, UseIntervals(Immediate(0).Intervals))
+	assert.Equal(t, ShutDown, err)
+	assert.Zero(t, calls)
+}
+
+func Test_Shutdown_Grace(t *testing.T) {
+	cases := map[string]struct {
+		grace        time.Duration
+		finish       bool
+		wantShutdown error
+		wantErr      error
+	}{
+		"In flight attempt finishes": {
+			grace:  time.Second,
+			finish: true,
+		},
+		"Grace period ends": {
+			grace:        20 * time.Millisecond,
+			wantShutdown: context.DeadlineExceeded,
+			wantErr:      ShutDown,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			bo := NewBackoff(minuteInterval)
+			started := make(chan struct{})
+			finish := make(chan struct{})
+			result := make(chan error)
+			go func() {
+				result <- bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+					close(started)
+					select {
+					case <-finish:
+						return true
+					case <-ctx.Done():
+						return false
+					}
+				})
+			}()
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.grace)
+			defer cancel()
+			shutdown := make(chan error)
+			go func() {
+				shutdown <- bo.Shutdown(ctx)
+			}()
+			if tc.finish {
+				time.Sleep(10 * time.Millisecond)
+				close(finish)
+			}
+
+			assert.Equal(t, tc.wantShutdown, <-shutdown)
+			assert.Equal(t, tc.wantErr, <-result)
+		})
+	}
+}
+
+func Test_ShutdownAll(t *testing.T) {
+	defer ResetShutdownAll()
+	bo := NewBackoff(minuteInterval)
+	require.NoError(t, ShutdownAll(context.Background()))
+
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		return true
+	})
+	assert.Equal(t, ShutDown, err)
+}
+
+func Test_ResetShutdownAll(t *testing.T) {
+	defer ResetShutdownAll()
+	bo := NewBackoff(minuteInterval)
+	require.NoError(t, ShutdownAll(context.Background()))
+	ResetShutdownAll()
+
+	err := bo.Try(context.Background(), 3, func(ctx context.Context) bool {
+		return true
+	})
+	assert.NoError(t, err)
+}
+
+func Test_shutdown_Concurrent(t *testing.T) {
+	s := newShutdown()
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s.enter() {
+				s.leave()
+			}
+		}()
+	}
+	require.NoError(t, s.shutdown(context.Background()))
+	wg.Wait()
+	assert.Equal(t, int64(shutdownClosed), s.state.Load())
+}
@@ -0,0 +1,87 @@
+package backoff
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+)
+
+// Single deduplicates concurrent Try and TryErr calls that share the same key.
+// Only the first caller for a key runs the retry loop, other callers block until
+// it finishes and receive the same result. This prevents many goroutines from
+// independently retrying the same failed operation, for example a cache fill.
+//
+// Since only the first caller runs the loop, its context and function are used
+// for the whole loop. Waiting callers only use their own context to stop
+// waiting.
+type Single struct {
+	backoff *Backoff
+
+	mu    sync.Mutex
+	calls map[string]*singleCall
+}
+
+type singleCall struct {
+	done chan struct{}
+	err  error
+}
+
+// NewSingle creates a new Single that runs retry loops with the provided
+// Backoff.
+func NewSingle(bo *Backoff) *Single {
+	return &Single{
+		backoff: bo,
+		calls:   make(map[string]*singleCall),
+	}
+}
+
+// Try calls Backoff.Try unless a call for the same key is already in flight in
+// which case it waits for that call and returns its result. If ctx is done while
+// waiting, Try returns BackoffContextTimeoutExceeded.
+func (s *Single) Try(ctx context.Context, key string, tries int8, fn Completable) error {
+	return s.do(ctx, key, func() error {
+		return s.backoff.Try(ctx, tries, fn)
+	})
+}
+
+// TryErr calls Backoff.TryErr unless a call for the same key is already in
+// flight in which case it waits for that call and returns its result. If ctx is
+// done while waiting, TryErr returns BackoffContextTimeoutExceeded.
+func (s *Single) TryErr(ctx context.Context, key string, tries int8, fn Operation) error {
+	return s.do(ctx, key, func() error {
+		return s.backoff.TryErr(ctx, tries, fn)
+	})
+}
+
+func (s *Single) do(ctx context.Context, key string, loop func() error) error {
+	s.mu.Lock()
+	if c, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return BackoffContextTimeoutExceeded
+		case <-c.done:
+			return c.err
+		}
+	}
+	c := &singleCall{done: make(chan struct{})}
+	s.calls[key] = c
+	s.mu.Unlock()
+
+	defer func() {
+		// waiters get a *PanicError if the loop panics, the panic continues
+		r := recover()
+		if r != nil {
+			c.err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+		s.mu.Lock()
+		delete(s.calls, key)
+		s.mu.Unlock()
+		close(c.done)
+		if r != nil {
+			panic(r)
+		}
+	}()
+	c.err = loop()
+	return c.err
+}
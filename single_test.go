@@ -0,0 +1,131 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Single_DeduplicatesConcurrentCalls(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	single := backoff.NewSingle(backoff.NewBackoff(shortInterval))
+	var calls int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) bool {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return false
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		close(started)
+		errs[0] = single.Try(ctx, "key", 1, fn)
+	}()
+	<-started
+	// wait for the first call to be in flight
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i < len(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = single.Try(ctx, "key", 1, fn)
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, err := range errs {
+		assert.Equal(t, backoff.AllTriesFailed, err)
+	}
+}
+
+func Test_Single_DifferentKeysRunIndependently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	single := backoff.NewSingle(backoff.NewBackoff(shortInterval))
+	var calls int32
+	fn := func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+
+	assert.NoError(t, single.TryErr(ctx, "a", 1, fn))
+	assert.NoError(t, single.TryErr(ctx, "b", 1, fn))
+	assert.NoError(t, single.TryErr(ctx, "a", 1, fn))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func Test_Single_WaiterContextDone(t *testing.T) {
+	single := backoff.NewSingle(backoff.NewBackoff(shortInterval))
+	release := make(chan struct{})
+	inFlight := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = single.Try(context.Background(), "key", 1, func(ctx context.Context) bool {
+			close(inFlight)
+			<-release
+			return true
+		})
+	}()
+	<-inFlight
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := single.Try(ctx, "key", 1, func(ctx context.Context) bool {
+		return true
+	})
+	assert.Equal(t, backoff.BackoffContextTimeoutExceeded, err)
+
+	close(release)
+	<-done
+}
+
+func Test_Single_WaiterGetsPanicError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	single := backoff.NewSingle(backoff.NewBackoff(shortInterval))
+	release := make(chan struct{})
+	inFlight := make(chan struct{})
+	leader := make(chan interface{})
+	go func() {
+		defer func() { leader <- recover() }()
+		_ = single.Try(ctx, "key", 1, func(ctx context.Context) bool {
+			close(inFlight)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-inFlight
+	waiter := make(chan error)
+	go func() {
+		waiter <- single.Try(ctx, "key", 1, func(ctx context.Context) bool { return true })
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "boom", <-leader)
+	var perr *backoff.PanicError
+	assert.True(t, errors.As(<-waiter, &perr))
+	assert.Equal(t, "boom", perr.Value)
+}
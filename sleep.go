@@ -0,0 +1,51 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Sleep pauses the current goroutine for at least the duration d or until the
+// context Done channel is closed, whichever happens first. If the context is
+// done before d elapses, Sleep returns the context error. A zero or negative
+// duration returns immediately.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SleepFor pauses for the interval the provided Intervals would use after the
+// given attempt (starting from 0) in Try. It is meant for callers managing
+// their own retry loops who still want the same series as Try.
+//
+// Since an Intervals implementation may depend on the `last` value, the series
+// is replayed from the first iteration to compute the wait. Attempts beyond
+// InfiniteTries use the InfiniteTries interval.
+func SleepFor(ctx context.Context, iv Intervals, attempt int) error {
+	return Sleep(ctx, intervalAt(iv, attempt))
+}
+
+// intervalAt replays the series of iv up to attempt and returns the interval
+// for that attempt
+func intervalAt(iv Intervals, attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt > InfiniteTries {
+		attempt = InfiniteTries
+	}
+	var wait time.Duration
+	for i := 0; i <= attempt; i++ {
+		wait = iv.Next(int8(i), wait)
+	}
+	return wait
+}
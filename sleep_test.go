@@ -0,0 +1,80 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Sleep(t *testing.T) {
+	cases := map[string]struct {
+		d       time.Duration
+		timeout time.Duration
+		wantErr error
+	}{
+		"Sleep completes": {
+			d:       10 * time.Millisecond,
+			timeout: time.Second,
+			wantErr: nil,
+		},
+		"Context times out first": {
+			d:       time.Second,
+			timeout: 10 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+		"Zero duration returns immediately": {
+			d:       0,
+			timeout: time.Second,
+			wantErr: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			err := backoff.Sleep(ctx, tc.d)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func Test_Sleep_CancelledContextWithZeroDuration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := backoff.Sleep(ctx, 0)
+	assert.Equal(t, context.Canceled, err)
+}
+
+type custom struct{}
+
+func (c custom) Next(i int8, last time.Duration) time.Duration {
+	if last == 0 {
+		return time.Millisecond
+	}
+	return last * 2
+}
+
+func Test_SleepFor(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	// the custom series depends on `last`: 1ms, 2ms, 4ms, 8ms
+	err := backoff.SleepFor(ctx, custom{}, 3)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) >= 8*time.Millisecond)
+}
+
+func Test_SleepFor_ContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := backoff.SleepFor(ctx, backoff.DefaultBinaryExponential(), 4)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
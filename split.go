@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlineSplit divides the time left until the deadline of the Try
+// context between the planned attempts. With fractions 0.5, 0.3 and 0.2 the
+// first attempt gets a deadline at half of the time left when Try was called,
+// the second attempt 30% and the third attempt 20%, so later attempts still
+// have budget. Attempts beyond the fractions, and all attempts when the context
+// has no deadline, are only limited by the Try context.
+func WithDeadlineSplit(fractions []float64) Options {
+	return func(bo *Backoff) {
+		bo.deadlineSplit = fractions
+	}
+}
+
+// splitBudget returns the time left until the deadline of ctx, or zero if
+// the deadline is not split.
+func (b *Backoff) splitBudget(ctx context.Context) time.Duration {
+	if len(b.deadlineSplit) == 0 {
+		return 0
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	return time.Until(deadline)
+}
+
+// splitDeadline limits ctx to the share of budget of attempt, starting from 1
+func (b *Backoff) splitDeadline(ctx context.Context, budget time.Duration, attempt int) (context.Context, context.CancelFunc) {
+	if budget <= 0 || attempt > len(b.deadlineSplit) {
+		return ctx, func() {}
+	}
+	share := time.Duration(float64(budget) * b.deadlineSplit[attempt-1])
+	return context.WithTimeout(ctx, share)
+}
@@ -0,0 +1,55 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithDeadlineSplit(t *testing.T) {
+	cases := map[string]struct {
+		timeout time.Duration // zero for no deadline
+		want    []time.Duration
+	}{
+		"Split the deadline": {
+			timeout: time.Second,
+			want:    []time.Duration{500 * time.Millisecond, 300 * time.Millisecond, 200 * time.Millisecond, time.Second},
+		},
+		"No deadline": {
+			want: []time.Duration{0, 0, 0, 0},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			start := time.Now()
+			var got []time.Duration
+			bo := backoff.NewBackoff(backoff.Immediate(0).Intervals, backoff.WithDeadlineSplit([]float64{0.5, 0.3, 0.2}))
+			err := bo.Try(ctx, 4, func(ctx context.Context) bool {
+				var left time.Duration
+				if deadline, ok := ctx.Deadline(); ok {
+					left = deadline.Sub(start)
+				}
+				got = append(got, left)
+				return false
+			})
+
+			assert.Equal(t, backoff.AllTriesFailed, err)
+			assert.Len(t, got, len(tc.want))
+			for i := range tc.want {
+				assert.InDelta(t, tc.want[i], got[i], float64(20*time.Millisecond), "attempt %d", i+1)
+			}
+		})
+	}
+}
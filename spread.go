@@ -0,0 +1,66 @@
+package backoff
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// SpreadOverDeadline divides the time left until the context deadline evenly
+// across the remaining tries, for example to poll for an asynchronous job
+// against a hard SLA. The remaining time is split into one segment per
+// remaining try plus a final segment left for the last attempt to run, so the
+// last attempt starts near but before the deadline. The gap is recomputed
+// after every attempt, so slow attempts shorten the following gaps.
+//
+// Each gap is reduced by a random fraction of up to Jitter when Rand is set,
+// and kept within [MinGap, MaxGap]; a zero MaxGap does not limit the gap.
+// Without a deadline or with InfiniteTries the gap is MaxGap, or MinGap if
+// MaxGap is zero.
+//
+// SpreadOverDeadline is an IntervalsV2, use it with NewBackoffV2:
+//
+//	bo := backoff.NewBackoffV2(backoff.SpreadOverDeadline{MinGap: time.Second, MaxGap: time.Minute})
+type SpreadOverDeadline struct {
+	MinGap time.Duration
+	MaxGap time.Duration
+	Jitter float64
+	Rand   *rand.Rand
+}
+
+var _ BudgetIntervals = SpreadOverDeadline{}
+
+// Next returns the gap used without a Budget.
+func (s SpreadOverDeadline) Next(attempt int, last time.Duration, elapsed time.Duration) time.Duration {
+	if s.MaxGap > 0 {
+		return s.MaxGap
+	}
+	return s.MinGap
+}
+
+// NextBudget spreads the remaining time of budget over the remaining tries.
+func (s SpreadOverDeadline) NextBudget(last time.Duration, budget Budget) time.Duration {
+	if !budget.HasDeadline() || budget.RemainingTries < 0 {
+		return s.Next(budget.Attempt, last, budget.Elapsed)
+	}
+	gap := budget.RemainingTime / time.Duration(budget.RemainingTries+1)
+	if s.Jitter > 0 && s.Rand != nil {
+		gap -= time.Duration(s.Jitter * s.Rand.Float64() * float64(gap))
+	}
+	if s.MaxGap > 0 && gap > s.MaxGap {
+		gap = s.MaxGap
+	}
+	if gap < s.MinGap {
+		gap = s.MinGap
+	}
+	return gap
+}
+
+// String describes the series, for example "spread over deadline 1s to 1m0s".
+func (s SpreadOverDeadline) String() string {
+	str := fmt.Sprintf("spread over deadline %s to %s", s.MinGap, s.MaxGap)
+	if s.Jitter > 0 {
+		str += fmt.Sprintf(" -%g%% jitter", s.Jitter*100)
+	}
+	return str
+}
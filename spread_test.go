@@ -0,0 +1,87 @@
+package backoff_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_SpreadOverDeadline_NextBudget(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+
+	cases := map[string]struct {
+		spread backoff.SpreadOverDeadline
+		budget backoff.Budget
+		want   time.Duration
+	}{
+		"Spread": {
+			budget: backoff.Budget{RemainingTries: 3, Deadline: deadline, RemainingTime: 40 * time.Second},
+			want:   10 * time.Second,
+		},
+		"Last try": {
+			budget: backoff.Budget{RemainingTries: 1, Deadline: deadline, RemainingTime: 40 * time.Second},
+			want:   20 * time.Second,
+		},
+		"Capped at MaxGap": {
+			spread: backoff.SpreadOverDeadline{MaxGap: 5 * time.Second},
+			budget: backoff.Budget{RemainingTries: 3, Deadline: deadline, RemainingTime: 40 * time.Second},
+			want:   5 * time.Second,
+		},
+		"Raised to MinGap": {
+			spread: backoff.SpreadOverDeadline{MinGap: 15 * time.Second},
+			budget: backoff.Budget{RemainingTries: 3, Deadline: deadline, RemainingTime: 40 * time.Second},
+			want:   15 * time.Second,
+		},
+		"No deadline": {
+			spread: backoff.SpreadOverDeadline{MinGap: time.Second, MaxGap: time.Minute},
+			budget: backoff.Budget{RemainingTries: 3},
+			want:   time.Minute,
+		},
+		"Infinite tries": {
+			spread: backoff.SpreadOverDeadline{MinGap: time.Second},
+			budget: backoff.Budget{RemainingTries: -1, Deadline: deadline, RemainingTime: 40 * time.Second},
+			want:   time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.want, tc.spread.NextBudget(0, tc.budget))
+		})
+	}
+}
+
+func Test_SpreadOverDeadline_Jitter(t *testing.T) {
+	s := backoff.SpreadOverDeadline{Jitter: 0.5, Rand: rand.New(rand.NewSource(1))}
+	budget := backoff.Budget{RemainingTries: 3, Deadline: time.Now().Add(time.Hour), RemainingTime: 40 * time.Second}
+	for n := 0; n < 100; n++ {
+		gap := s.NextBudget(0, budget)
+		assert.True(t, gap > 5*time.Second && gap <= 10*time.Second, "gap %s", gap)
+	}
+}
+
+func Test_SpreadOverDeadline_Try(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	bo := backoff.NewBackoffV2(backoff.SpreadOverDeadline{})
+
+	start := time.Now()
+	var last time.Duration
+	calls := 0
+	err := bo.Try(ctx, 4, func(ctx context.Context) bool {
+		calls++
+		last = time.Since(start)
+		return false
+	})
+	assert.Equal(t, backoff.AllTriesFailed, err)
+	assert.Equal(t, 4, calls)
+	// the last attempt runs in the final segment before the deadline
+	assert.Greater(t, last, 100*time.Millisecond)
+	assert.Less(t, last, 200*time.Millisecond)
+}
@@ -0,0 +1,122 @@
+package backoff
+
+import (
+	"sync"
+	"time"
+)
+
+// StormDetector detects retry storms across all Backoff instances that share
+// it. When the rate of retries stays above a threshold for a sustained period,
+// the alarm callback is called once and, optionally, all backoff pauses of the
+// sharing instances are stretched by a multiplier until the rate drops below
+// the threshold again. This protects shared dependencies during incidents.
+//
+// The rate is measured in retries per second over one second buckets. A
+// StormDetector is safe for concurrent use.
+type StormDetector struct {
+	threshold float64
+	sustain   time.Duration
+	stretch   float64
+	onAlarm   func(rate float64)
+	now       func() time.Time
+
+	mu          sync.Mutex
+	bucketStart time.Time
+	count       int
+	aboveSince  time.Time
+	alarmed     bool
+}
+
+// NewStormDetector creates a new StormDetector. The storm alarm fires when more
+// than `threshold` retries per second happen for at least `sustain`. While the
+// storm lasts, pauses are multiplied by `stretch`; use 1 to only be alarmed.
+// onAlarm is called once per storm with the measured rate and may be nil.
+func NewStormDetector(threshold float64, sustain time.Duration, stretch float64, onAlarm func(rate float64)) *StormDetector {
+	return &StormDetector{
+		threshold: threshold,
+		sustain:   sustain,
+		stretch:   stretch,
+		onAlarm:   onAlarm,
+		now:       time.Now,
+	}
+}
+
+// WithStormDetector reports every retry of the Backoff to the StormDetector and
+// stretches the backoff pause while a storm is detected. Share the same
+// StormDetector between instances to detect storms across them.
+func WithStormDetector(sd *StormDetector) Options {
+	return func(bo *Backoff) {
+		bo.storm = sd
+	}
+}
+
+// Storming reports if a storm is currently detected.
+func (s *StormDetector) Storming() bool {
+	s.mu.Lock()
+	alarm, rate := s.roll(s.now())
+	alarmed := s.alarmed
+	s.mu.Unlock()
+	s.alarm(alarm, rate)
+	return alarmed
+}
+
+// stretchWait records a retry and returns the wait stretched if a storm is
+// detected. It is safe to call on a nil StormDetector.
+func (s *StormDetector) stretchWait(wait time.Duration) time.Duration {
+	if s == nil {
+		return wait
+	}
+	s.mu.Lock()
+	alarm, rate := s.roll(s.now())
+	s.count++
+	alarmed := s.alarmed
+	s.mu.Unlock()
+	s.alarm(alarm, rate)
+	if alarmed && s.stretch > 0 {
+		return time.Duration(float64(wait) * s.stretch)
+	}
+	return wait
+}
+
+// roll closes the current bucket if it is over and evaluates its rate. It
+// reports if the alarm went off, to be passed to alarm once s.mu is released.
+// s.mu must be held.
+func (s *StormDetector) roll(now time.Time) (alarm bool, rate float64) {
+	if s.bucketStart.IsZero() {
+		s.bucketStart = now
+		return false, 0
+	}
+	elapsed := now.Sub(s.bucketStart)
+	if elapsed < time.Second {
+		return false, 0
+	}
+	rate = float64(s.count)
+	if elapsed >= 2*time.Second {
+		// the buckets in between had no retries
+		rate = 0
+	}
+	s.bucketStart = s.bucketStart.Add(elapsed.Truncate(time.Second))
+	s.count = 0
+
+	if rate <= s.threshold {
+		s.aboveSince = time.Time{}
+		s.alarmed = false
+		return false, rate
+	}
+	if s.aboveSince.IsZero() {
+		s.aboveSince = now.Add(-elapsed)
+	}
+	if !s.alarmed && now.Sub(s.aboveSince) >= s.sustain {
+		s.alarmed = true
+		return true, rate
+	}
+	return false, rate
+}
+
+// alarm calls onAlarm if the alarm went off. s.mu must not be held so onAlarm
+// can call the StormDetector.
+func (s *StormDetector) alarm(alarm bool, rate float64) {
+	if alarm && s.onAlarm != nil {
+		s.onAlarm(rate)
+	}
+}
@@ -0,0 +1,88 @@
+package backoff
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StormDetector(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	var alarms []float64
+	sd := NewStormDetector(5, 2*time.Second, 3, func(rate float64) {
+		alarms = append(alarms, rate)
+	})
+	sd.now = func() time.Time { return now }
+
+	retries := func(n int) {
+		for i := 0; i < n; i++ {
+			sd.stretchWait(time.Second)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// below the threshold
+	retries(5)
+	retries(5)
+	assert.False(t, sd.Storming())
+
+	// above the threshold, but not sustained yet
+	retries(10)
+	assert.False(t, sd.Storming())
+
+	// sustained for 2 seconds
+	retries(9)
+	assert.True(t, sd.Storming())
+	assert.Equal(t, []float64{9}, alarms)
+	assert.Equal(t, 3*time.Second, sd.stretchWait(time.Second))
+
+	// the alarm only fires once per storm
+	retries(10)
+	assert.True(t, sd.Storming())
+	assert.Len(t, alarms, 1)
+
+	// quiet period ends the storm
+	now = now.Add(5 * time.Second)
+	assert.False(t, sd.Storming())
+	assert.Equal(t, time.Second, sd.stretchWait(time.Second))
+}
+
+func Test_WithStormDetector_StretchesWaits(t *testing.T) {
+	sd := NewStormDetector(0, 0, 2, nil)
+	sd.alarmed = true
+	sd.bucketStart = time.Now()
+
+	ds, afterFn := afterFnLogger()
+	shortInterval := Exponential{
+		Base:    2 * time.Millisecond,
+		Unit:    time.Millisecond,
+		Initial: 1 * time.Millisecond,
+		Max:     20 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := NewBackoff(shortInterval, withAfterFunc(afterFn), WithStormDetector(sd))
+	err := bo.Try(ctx, 3, func(ctx context.Context) bool { return false })
+
+	assert.Equal(t, AllTriesFailed, err)
+	assert.Equal(t, []time.Duration{2 * time.Millisecond, 4 * time.Millisecond}, ds.durations)
+}
+
+func Test_StormDetector_AlarmCallsBack(t *testing.T) {
+	now := time.Date(2019, 7, 19, 0, 0, 0, 0, time.UTC)
+	var storming []bool
+	var sd *StormDetector
+	sd = NewStormDetector(0, 0, 2, func(rate float64) {
+		storming = append(storming, sd.Storming())
+	})
+	sd.now = func() time.Time { return now }
+
+	sd.stretchWait(time.Second)
+	now = now.Add(time.Second)
+	sd.stretchWait(time.Second)
+	now = now.Add(time.Second)
+	assert.True(t, sd.Storming())
+	assert.Equal(t, []bool{true}, storming)
+}
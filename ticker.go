@@ -0,0 +1,80 @@
+package backoff
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// JitteredTicker delivers ticks like time.Ticker but re-jitters the period
+// before every tick, so steady state polling of many instances does not
+// synchronize. It complements a backoff for the phase after recovery.
+type JitteredTicker struct {
+	// C is the channel on which the ticks are delivered
+	C <-chan time.Time
+
+	period   time.Duration
+	jitter   float64
+	rand     *rand.Rand
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewJitteredTicker creates a new JitteredTicker. Each period is adjusted by a
+// random value between +/- jitterFraction * period, for example a jitterFraction
+// of 0.1 with a period of 10s ticks every 9s to 11s. It panics if period is not
+// positive, like time.NewTicker. Stop the ticker to release its resources.
+func NewJitteredTicker(period time.Duration, jitterFraction float64) *JitteredTicker {
+	if period <= 0 {
+		panic("backoff: non-positive period for NewJitteredTicker")
+	}
+	c := make(chan time.Time, 1)
+	t := &JitteredTicker{
+		C:      c,
+		period: period,
+		jitter: jitterFraction,
+		rand:   rand.New(newLockedSource()),
+		stop:   make(chan struct{}),
+	}
+	go t.run(c)
+	return t
+}
+
+// Stop turns off the ticker. Like time.Ticker, Stop does not close the
+// channel.
+func (t *JitteredTicker) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
+
+func (t *JitteredTicker) run(c chan<- time.Time) {
+	timer := time.NewTimer(t.next())
+	defer timer.Stop()
+	for {
+		select {
+		case <-t.stop:
+			return
+		case now := <-timer.C:
+			// drop the tick for slow receivers, like time.Ticker
+			select {
+			case c <- now:
+			default:
+			}
+			timer.Reset(t.next())
+		}
+	}
+}
+
+// next returns the period adjusted by a random jitter
+func (t *JitteredTicker) next() time.Duration {
+	return jitterPeriod(t.rand, t.period, t.jitter)
+}
+
+// jitterPeriod adjusts period by a random value between +/- fraction * period
+func jitterPeriod(r *rand.Rand, period time.Duration, fraction float64) time.Duration {
+	jitter := (r.Float64()*2 - 1) * fraction * float64(period)
+	d := period + time.Duration(jitter)
+	if d <= 0 {
+		return time.Nanosecond
+	}
+	return d
+}
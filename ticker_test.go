@@ -0,0 +1,40 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_JitteredTicker_NextWithinRange(t *testing.T) {
+	ticker := NewJitteredTicker(time.Second, 0.1)
+	defer ticker.Stop()
+
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 1000; i++ {
+		d := ticker.next()
+		assert.GreaterOrEqual(t, d, 900*time.Millisecond)
+		assert.LessOrEqual(t, d, 1100*time.Millisecond)
+		seen[d] = true
+	}
+	assert.Greater(t, len(seen), 1, "the period should be re-jittered")
+}
+
+func Test_JitteredTicker_Ticks(t *testing.T) {
+	ticker := NewJitteredTicker(5*time.Millisecond, 0.5)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-ticker.C:
+		case <-time.After(time.Second):
+			t.Fatal("no tick")
+		}
+	}
+	ticker.Stop()
+	ticker.Stop() // stopping twice is a no-op
+}
+
+func Test_NewJitteredTicker_PanicsOnNonPositivePeriod(t *testing.T) {
+	assert.Panics(t, func() { NewJitteredTicker(0, 0.1) })
+}
@@ -0,0 +1,27 @@
+package backoff
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// WithTrace emits a runtime/trace task named `name` for each Try call, with a
+// region for each attempt and each backoff pause, so `go tool trace` shows the
+// time spent attempting and the time spent waiting. The regions are emitted by
+// TraceMiddleware(name), which WithTrace adds to the middleware chain.
+func WithTrace(name string) Options {
+	return func(bo *Backoff) {
+		bo.traceName = name
+		WithMiddleware(TraceMiddleware(name))(bo)
+	}
+}
+
+// traceTask starts the task of a Try call. It returns ctx unchanged and a no-op
+// end function when tracing is not enabled.
+func (b *Backoff) traceTask(ctx context.Context) (context.Context, func()) {
+	if b.traceName == "" {
+		return ctx, func() {}
+	}
+	ctx, task := trace.NewTask(ctx, b.traceName)
+	return ctx, task.End
+}
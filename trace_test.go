@@ -0,0 +1,32 @@
+package backoff_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_WithTrace(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("tracing is already enabled")
+	}
+	var buf bytes.Buffer
+	require.NoError(t, trace.Start(&buf))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	bo := backoff.NewBackoff(shortInterval, backoff.WithTrace("fetch-orders"))
+	err := bo.TryErr(ctx, 3, failWith(assert.AnError))
+	trace.Stop()
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "fetch-orders")
+	assert.Contains(t, buf.String(), "backoff pause")
+}
@@ -0,0 +1,79 @@
+package backoff
+
+import "context"
+
+const (
+	// NilContext is returned by Try and TryErr when called with a nil context
+	NilContext = Error("nil context")
+	// NilOperation is returned by Try and TryErr when called with a nil
+	// function
+	NilOperation = Error("nil operation")
+	// NilIntervals is returned by Try and TryErr when the Backoff has no
+	// Intervals series
+	NilIntervals = Error("nil intervals")
+	// NegativeTries is returned by Try and TryErr when called with tries below
+	// zero
+	NegativeTries = Error("negative tries")
+	// ZeroTries is returned by Try and TryErr when called with zero tries and
+	// the ZeroTriesNone policy
+	ZeroTries = Error("zero tries")
+)
+
+// ZeroTriesPolicy decides what Try does when called with zero tries.
+type ZeroTriesPolicy int
+
+const (
+	// ZeroTriesOnce makes a single attempt, like tries of 1. This is the
+	// default for compatibility.
+	ZeroTriesOnce ZeroTriesPolicy = iota
+	// ZeroTriesNone makes no attempt and returns ZeroTries
+	ZeroTriesNone
+	// ZeroTriesDefault uses the tries of DefaultPolicy
+	ZeroTriesDefault
+)
+
+// WithZeroTries sets what Try does when called with zero tries. A zero often
+// comes from an unset configuration value, where making a single attempt is
+// surprising.
+func WithZeroTries(policy ZeroTriesPolicy) Options {
+	return func(bo *Backoff) {
+		bo.zeroTries = policy
+	}
+}
+
+// MustNewBackoff is like NewBackoff but panics if intervals is nil. Use it for
+// package level Backoff variables where a missing series is a programmer
+// error.
+func MustNewBackoff(intervals Intervals, options ...Options) *Backoff {
+	if intervals == nil {
+		panic(NilIntervals)
+	}
+	return NewBackoff(intervals, options...)
+}
+
+// withZeroTries applies the ZeroTriesPolicy to tries
+func (b *Backoff) withZeroTries(tries int8) (int8, error) {
+	if tries != 0 {
+		return tries, nil
+	}
+	switch b.zeroTries {
+	case ZeroTriesNone:
+		return 0, ZeroTries
+	case ZeroTriesDefault:
+		return DefaultPolicy().Tries, nil
+	}
+	return tries, nil
+}
+
+// validate checks the arguments of a Try call before the loop starts
+func validate(ctx context.Context, tries int8, fn Operation) error {
+	switch {
+	case ctx == nil:
+		return NilContext
+	case fn == nil:
+		return NilOperation
+	case tries < 0:
+		return NegativeTries
+	}
+	return nil
+}
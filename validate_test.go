@@ -0,0 +1,106 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_TryInvalidArguments(t *testing.T) {
+	ok := func(ctx context.Context) bool { return true }
+
+	cases := map[string]struct {
+		ctx       context.Context
+		intervals backoff.Intervals
+		tries     int8
+		fn        backoff.Completable
+		wantErr   error
+	}{
+		"Nil context": {
+			intervals: shortInterval,
+			tries:     3,
+			fn:        ok,
+			wantErr:   backoff.NilContext,
+		},
+		"Nil function": {
+			ctx:       context.Background(),
+			intervals: shortInterval,
+			tries:     3,
+			wantErr:   backoff.NilOperation,
+		},
+		"Nil intervals": {
+			ctx:     context.Background(),
+			tries:   3,
+			fn:      ok,
+			wantErr: backoff.NilIntervals,
+		},
+		"Negative tries": {
+			ctx:       context.Background(),
+			intervals: shortInterval,
+			tries:     -1,
+			fn:        ok,
+			wantErr:   backoff.NegativeTries,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			bo := backoff.NewBackoff(tc.intervals)
+			assert.Equal(t, tc.wantErr, bo.Try(tc.ctx, tc.tries, tc.fn))
+
+			var op backoff.Operation
+			if tc.fn != nil {
+				op = func(ctx context.Context) error { return nil }
+			}
+			assert.Equal(t, tc.wantErr, bo.TryErr(tc.ctx, tc.tries, op))
+		})
+	}
+}
+
+func Test_MustNewBackoff(t *testing.T) {
+	assert.NotNil(t, backoff.MustNewBackoff(shortInterval))
+	assert.PanicsWithValue(t, backoff.NilIntervals, func() {
+		backoff.MustNewBackoff(nil)
+	})
+}
+
+func Test_TryZeroTries(t *testing.T) {
+	cases := map[string]struct {
+		option    backoff.Options
+		wantErr   error
+		wantCalls int
+	}{
+		"Once by default": {
+			option:    func(bo *backoff.Backoff) {},
+			wantErr:   backoff.AllTriesFailed,
+			wantCalls: 1,
+		},
+		"None": {
+			option:  backoff.WithZeroTries(backoff.ZeroTriesNone),
+			wantErr: backoff.ZeroTries,
+		},
+		"Policy default": {
+			option:    backoff.WithZeroTries(backoff.ZeroTriesDefault),
+			wantErr:   backoff.AllTriesFailed,
+			wantCalls: int(backoff.DefaultPolicy().Tries),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			calls := 0
+			bo := backoff.NewBackoff(shortInterval, tc.option)
+			err := bo.Try(context.Background(), 0, func(ctx context.Context) bool {
+				calls++
+				return false
+			})
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCalls, calls)
+		})
+	}
+}
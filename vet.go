@@ -0,0 +1,113 @@
+package backoff
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Warning is a dangerous configuration found by Vet.
+type Warning struct {
+	// Check names the check that found the problem, like "no-jitter"
+	Check   string
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Check + ": " + w.Message
+}
+
+// Vet inspects a policy for dangerous configurations. Intervals other than
+// Exponential and ExponentialJitter are only checked for the number of tries,
+// since calling Next may change the state of a stateful series.
+//
+//   - no-jitter: the series is deterministic so many clients that fail
+//     together retry in lockstep
+//   - unbounded: InfiniteTries never gives up unless the context has a deadline
+//   - no-intervals: the policy has no Intervals
+//   - tiny-interval: an initial interval under a millisecond is effectively a
+//     busy loop
+//   - max-below-initial: an Exponential series whose Max is lower than Initial
+//     never grows
+//   - no-growth: an Exponential series whose Base is not larger than Unit
+//     never grows
+//   - jitter-exceeds-interval: the jitter is larger than the interval so the
+//     pause can be negative
+//
+// Vet returns nil if no problems are found.
+func Vet(p Policy) []Warning {
+	var warnings []Warning
+	add := func(check, format string, args ...interface{}) {
+		warnings = append(warnings, Warning{Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if p.Intervals == nil {
+		add("no-intervals", "the policy has no Intervals")
+		return warnings
+	}
+	if p.Tries == InfiniteTries {
+		add("unbounded", "InfiniteTries only stops when the context is done, make sure it has a deadline")
+	}
+
+	var exp *Exponential
+	var jitter time.Duration
+	switch iv := p.Intervals.(type) {
+	case Exponential:
+		exp = &iv
+		add("no-jitter", "the series has no jitter so clients that fail together retry together")
+	case ExponentialJitter:
+		exp = &iv.Exponential
+		jitter = iv.JitterMax
+		if iv.JitterMax <= 0 && iv.RandomizationFactor <= 0 {
+			add("no-jitter", "the series has no jitter so clients that fail together retry together")
+		}
+		if iv.RandomizationFactor > 1 {
+			add("jitter-exceeds-interval", "randomization factor %g is larger than 1", iv.RandomizationFactor)
+		}
+	}
+	if exp != nil {
+		if exp.Max < exp.Initial {
+			add("max-below-initial", "Max %s is lower than Initial %s", exp.Max, exp.Initial)
+		}
+		if exp.Unit > 0 && exp.Base <= exp.Unit {
+			add("no-growth", "Base %s is not larger than Unit %s so the series does not grow", exp.Base, exp.Unit)
+		}
+		if jitter > exp.Initial {
+			add("jitter-exceeds-interval", "jitter ±%s is larger than the initial interval %s", jitter, exp.Initial)
+		}
+		if exp.Initial > 0 && exp.Initial < time.Millisecond {
+			add("tiny-interval", "the initial interval %s is under a millisecond", exp.Initial)
+		}
+	}
+	return warnings
+}
+
+var (
+	vetMu     sync.Mutex
+	vetLogger = log.Default()
+)
+
+// SetVetLogger sets the logger NewPolicy reports Vet warnings to. The default
+// is the standard logger; nil disables the reports.
+func SetVetLogger(l *log.Logger) {
+	vetMu.Lock()
+	defer vetMu.Unlock()
+	vetLogger = l
+}
+
+// NewPolicy creates a named Policy and reports the warnings of Vet for it to
+// the logger set with SetVetLogger.
+func NewPolicy(name string, iv Intervals, tries int8) Policy {
+	p := Policy{Name: name, Intervals: iv, Tries: tries}
+	warnings := Vet(p)
+	vetMu.Lock()
+	defer vetMu.Unlock()
+	if vetLogger == nil {
+		return p
+	}
+	for _, w := range warnings {
+		vetLogger.Printf("backoff: policy %q: %s", name, w)
+	}
+	return p
+}
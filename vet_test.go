@@ -0,0 +1,104 @@
+package backoff_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rhomel/backoff"
+)
+
+func Test_Vet(t *testing.T) {
+	checks := func(ws []backoff.Warning) []string {
+		var out []string
+		for _, w := range ws {
+			out = append(out, w.Check)
+		}
+		return out
+	}
+
+	cases := map[string]struct {
+		policy backoff.Policy
+		want   []string
+	}{
+		"Preset is clean": {
+			policy: backoff.PresetAPIClient(),
+		},
+		"Database preset is clean": {
+			policy: backoff.PresetDatabase(),
+		},
+		"User facing preset is clean": {
+			policy: backoff.PresetUserFacing(),
+		},
+		"None is clean": {
+			policy: backoff.None(),
+		},
+		"Infinite preset": {
+			policy: backoff.PresetMessageBroker(),
+			want:   []string{"unbounded"},
+		},
+		"Default binary exponential has no jitter": {
+			policy: backoff.Policy{Intervals: backoff.DefaultBinaryExponential(), Tries: 5},
+			want:   []string{"no-jitter"},
+		},
+		"No intervals": {
+			policy: backoff.Policy{Tries: 5},
+			want:   []string{"no-intervals"},
+		},
+		"Broken exponential": {
+			policy: backoff.Policy{
+				Intervals: backoff.ExponentialJitter{
+					Exponential: backoff.Exponential{
+						Base:    time.Millisecond,
+						Unit:    time.Millisecond,
+						Initial: 100 * time.Microsecond,
+						Max:     50 * time.Microsecond,
+					},
+					JitterMax: time.Millisecond,
+				},
+				Tries: 5,
+			},
+			want: []string{"max-below-initial", "no-growth", "jitter-exceeds-interval", "tiny-interval"},
+		},
+		"Custom intervals": {
+			policy: backoff.Policy{Intervals: backoff.NewRampUp(backoff.DefaultBinaryExponential(), 3), Tries: 5},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc := tc
+			assert.Equal(t, tc.want, checks(backoff.Vet(tc.policy)))
+		})
+	}
+}
+
+func Test_NewPolicy_LogsWarnings(t *testing.T) {
+	var buf bytes.Buffer
+	backoff.SetVetLogger(log.New(&buf, "", 0))
+	defer backoff.SetVetLogger(log.Default())
+
+	p := backoff.NewPolicy("orders", backoff.DefaultBinaryExponential(), backoff.InfiniteTries)
+
+	assert.Equal(t, "orders", p.Name)
+	assert.Contains(t, buf.String(), `backoff: policy "orders": unbounded: `)
+	assert.Contains(t, buf.String(), `backoff: policy "orders": no-jitter: `)
+
+	buf.Reset()
+	backoff.SetVetLogger(nil)
+	backoff.NewPolicy("orders", backoff.DefaultBinaryExponential(), backoff.InfiniteTries)
+	assert.Empty(t, buf.String())
+}
+
+func Test_Presets_DoNotLog(t *testing.T) {
+	var buf bytes.Buffer
+	backoff.SetVetLogger(log.New(&buf, "", 0))
+	defer backoff.SetVetLogger(log.Default())
+
+	backoff.PresetMessageBroker()
+	backoff.Immediate(backoff.InfiniteTries)
+	assert.Empty(t, buf.String())
+}